@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/smartystreets/smartystreets-go-sdk/international-street-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+func DumpDiffs(lookup *street.Lookup, colored bool) string {
+	if len(lookup.Results) == 0 {
+		return "No candidates\n"
+	}
+	builder := new(strings.Builder)
+	for index, candidate := range lookup.Results {
+		fmt.Fprintf(builder, "Candidate %d:\n", index)
+		builder.WriteString(orNoChanges(helps.DumpDiff(diffFields(lookup, candidate), colored)))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func orNoChanges(diff string) string {
+	if diff == "" {
+		return "No changes\n"
+	}
+	return diff
+}
+
+func diffFields(lookup *street.Lookup, candidate *street.Candidate) []helps.FieldDiff {
+	components := candidate.Components
+	return []helps.FieldDiff{
+		{Field: "organization", Input: lookup.Organization, Output: candidate.Organization},
+		{Field: "freeform", Input: lookup.Freeform, Output: freeformOrBlank(lookup, candidate)},
+		{Field: "address1", Input: lookup.Address1, Output: candidate.Address1},
+		{Field: "address2", Input: lookup.Address2, Output: candidate.Address2},
+		{Field: "address3", Input: lookup.Address3, Output: candidate.Address3},
+		{Field: "address4", Input: lookup.Address4, Output: candidate.Address4},
+		{Field: "locality", Input: lookup.Locality, Output: components.Locality},
+		{Field: "administrative_area", Input: lookup.AdministrativeArea, Output: components.AdministrativeArea},
+		{Field: "postal_code", Input: lookup.PostalCode, Output: components.PostalCode},
+		{Field: "country", Input: lookup.Country, Output: components.CountryISO3},
+	}
+}
+
+// A freeform input is spread across the candidate's address lines,
+// so it is compared against all of them joined together.
+func freeformOrBlank(lookup *street.Lookup, candidate *street.Candidate) string {
+	if lookup.Freeform == "" {
+		return ""
+	}
+	return addressLines(candidate)
+}
+
+func addressLines(candidate *street.Candidate) string {
+	var lines []string
+	for _, line := range []string{
+		candidate.Address1, candidate.Address2, candidate.Address3, candidate.Address4,
+		candidate.Address5, candidate.Address6, candidate.Address7, candidate.Address8,
+	} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, ", ")
+}
@@ -32,7 +32,13 @@ func main() {
 	}
 
 	log.Println("Formatted Result:")
-	fmt.Println(helps.DumpJSON(lookup.Results))
+	if dump, found := helps.PointFormats[inputs.format]; found {
+		fmt.Println(dump(Points(lookup.Results)))
+	} else if inputs.diff {
+		fmt.Print(DumpDiffs(lookup, inputs.color))
+	} else {
+		fmt.Println(helps.DumpJSON(lookup.Results))
+	}
 }
 
 ///////////////////
@@ -56,6 +62,10 @@ type Inputs struct {
 	postalCode         string
 	geocode            bool
 
+	format string
+	diff   bool
+	color  bool
+
 	lookup *street.Lookup
 }
 
@@ -89,7 +99,14 @@ func (this *Inputs) flags() {
 	flag.StringVar(&this.administrativeArea, "administrative_area", "", "The administrative_area field.")
 	flag.StringVar(&this.postalCode, "postal_code", "", "The postal_code field.")
 	flag.BoolVar(&this.geocode, "geocode", true, "The geocode field.")
+	flag.StringVar(&this.format, "format", helps.FormatJSON, "The output format (choose from: "+helps.PointFormatNames()+").")
+	flag.BoolVar(&this.diff, "diff", false, "Show each input field lined up against the standardized candidate instead of JSON.")
+	flag.BoolVar(&this.color, "color", helps.IsTerminal(os.Stdout), "Colorize the -diff output (defaults to true when writing to a terminal).")
 	this.ParseFlags()
+
+	if !helps.IsPointFormat(this.format) {
+		log.Fatal("Unrecognized -format value:", this.format)
+	}
 }
 
 func (this *Inputs) AssembleLookup() *street.Lookup {
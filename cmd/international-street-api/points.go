@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/smartystreets/smartystreets-go-sdk/international-street-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+// Points skips candidates that weren't geocoded.
+func Points(candidates []*street.Candidate) (points []helps.Point) {
+	for _, candidate := range candidates {
+		metadata := candidate.Metadata
+		if metadata.Latitude == 0 && metadata.Longitude == 0 {
+			continue
+		}
+		points = append(points, helps.Point{
+			Latitude:  metadata.Latitude,
+			Longitude: metadata.Longitude,
+			Name:      addressLines(candidate),
+			Description: helps.Describe(
+				"premise", candidate.Components.Premise,
+				"thoroughfare", candidate.Components.Thoroughfare,
+				"sub_building", candidate.Components.SubBuilding,
+				"dependent_locality", candidate.Components.DependentLocality,
+				"locality", candidate.Components.Locality,
+				"administrative_area", candidate.Components.AdministrativeArea,
+				"postal_code", candidate.Components.PostalCode,
+				"country_iso_3", candidate.Components.CountryISO3,
+			),
+			Properties: map[string]interface{}{
+				"input_id":            candidate.InputID,
+				"organization":        candidate.Organization,
+				"address1":            candidate.Address1,
+				"address2":            candidate.Address2,
+				"address3":            candidate.Address3,
+				"locality":            candidate.Components.Locality,
+				"administrative_area": candidate.Components.AdministrativeArea,
+				"postal_code":         candidate.Components.PostalCode,
+				"country_iso_3":       candidate.Components.CountryISO3,
+				"geocode_precision":   metadata.GeocodePrecision,
+				"verification_status": candidate.Analysis.VerificationStatus,
+			},
+		})
+	}
+	return points
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+
+	international "github.com/smartystreets/smartystreets-go-sdk/international-street-api"
+	"github.com/smartystreets/smartystreets-go-sdk/us-autocomplete-api"
+	"github.com/smartystreets/smartystreets-go-sdk/us-extract-api"
+	reverse "github.com/smartystreets/smartystreets-go-sdk/us-reverse-geo-api"
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+	"github.com/smartystreets/smartystreets-go-sdk/us-zipcode-api"
+	"github.com/smartystreets/smartystreets-go-sdk/wireup"
+
+	"github.com/mdwhatcott/smarty-cli"
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+// Each api sends the fields set in the session (named like the -query keys of the corresponding
+// command) and returns the result along with any geocoded points.
+var apis = map[string]func(*clients, url.Values) (interface{}, []helps.Point, error){
+	"street":        sendStreet,
+	"international": sendInternational,
+	"zipcode":       sendZIPCode,
+	"autocomplete":  sendAutocomplete,
+	"extract":       sendExtract,
+	"reverse":       sendReverse,
+}
+
+func apiNames() (names []string) {
+	for name := range apis {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sendStreet(clients *clients, values url.Values) (interface{}, []helps.Point, error) {
+	lookup := cli.NewStreetLookup(values)
+	if lookup.Street == "" {
+		return nil, nil, fmt.Errorf("no street provided (set street <value> or set address <value>)")
+	}
+	batch := street.NewBatch()
+	batch.Append(lookup)
+	if err := clients.Street().SendBatch(batch); err != nil {
+		return nil, nil, err
+	}
+	var points []helps.Point
+	for _, candidate := range lookup.Results {
+		points = append(points, helps.Point{
+			Latitude:  candidate.Metadata.Latitude,
+			Longitude: candidate.Metadata.Longitude,
+			Name:      candidate.DeliveryLine1 + ", " + candidate.LastLine,
+		})
+	}
+	return lookup.Results, points, nil
+}
+
+func sendInternational(clients *clients, values url.Values) (interface{}, []helps.Point, error) {
+	lookup := &international.Lookup{
+		Country:            values.Get("country"),
+		Language:           international.Language(values.Get("language")),
+		Organization:       values.Get("organization"),
+		Freeform:           values.Get("freeform"),
+		Address1:           values.Get("address1"),
+		Address2:           values.Get("address2"),
+		Address3:           values.Get("address3"),
+		Address4:           values.Get("address4"),
+		Locality:           values.Get("locality"),
+		AdministrativeArea: values.Get("administrative_area"),
+		PostalCode:         values.Get("postal_code"),
+		Geocode:            values.Get("geocode") != "false",
+	}
+	if lookup.Freeform == "" && lookup.Address1 == "" {
+		return nil, nil, fmt.Errorf("no address provided (set freeform <value> or set address1 <value>)")
+	}
+	if err := clients.International().SendLookup(lookup); err != nil {
+		return nil, nil, err
+	}
+	var points []helps.Point
+	for _, candidate := range lookup.Results {
+		points = append(points, helps.Point{
+			Latitude:  candidate.Metadata.Latitude,
+			Longitude: candidate.Metadata.Longitude,
+			Name:      candidate.Address1,
+		})
+	}
+	return lookup.Results, points, nil
+}
+
+func sendZIPCode(clients *clients, values url.Values) (interface{}, []helps.Point, error) {
+	lookup := cli.NewZIPCodeLookup(values)
+	if lookup.City == "" && lookup.State == "" && lookup.ZIPCode == "" {
+		return nil, nil, fmt.Errorf("no data provided (set city, state and/or zipcode)")
+	}
+	batch := zipcode.NewBatch()
+	batch.Append(lookup)
+	if err := clients.ZIPCode().SendBatch(batch); err != nil {
+		return nil, nil, err
+	}
+	var points []helps.Point
+	if lookup.Result != nil {
+		for _, zip := range lookup.Result.ZIPCodes {
+			points = append(points, helps.Point{Latitude: zip.Latitude, Longitude: zip.Longitude, Name: zip.ZIPCode})
+		}
+	}
+	return lookup.Result, points, nil
+}
+
+func sendAutocomplete(clients *clients, values url.Values) (interface{}, []helps.Point, error) {
+	lookup := &autocomplete.Lookup{Prefix: values.Get("prefix")}
+	if lookup.Prefix == "" {
+		return nil, nil, fmt.Errorf("no prefix provided (set prefix <value>)")
+	}
+	cityFilter, stateFilter, prefer := cli.NewFilterListFlag(), cli.NewFilterListFlag(), cli.NewPreferenceListFlag()
+	for list, value := range map[*cli.ListFlag]string{
+		cityFilter:  values.Get("city_filter"),
+		stateFilter: values.Get("state_filter"),
+		prefer:      values.Get("prefer"),
+	} {
+		if err := list.Set(value); err != nil {
+			return nil, nil, err
+		}
+	}
+	lookup.CityFilter, lookup.StateFilter, lookup.Preferences = cityFilter.Values, stateFilter.Values, prefer.Values
+	lookup.PreferRatio, _ = strconv.ParseFloat(values.Get("prefer_ratio"), 64)
+	lookup.MaxSuggestions, _ = strconv.Atoi(values.Get("suggestions"))
+	if err := clients.Autocomplete().SendLookup(lookup); err != nil {
+		return nil, nil, err
+	}
+	return lookup.Results, nil, nil
+}
+
+func sendExtract(clients *clients, values url.Values) (interface{}, []helps.Point, error) {
+	lookup := &extract.Lookup{
+		Text:                    values.Get("text"),
+		HTML:                    extract.HTMLPayload(values.Get("html")),
+		AddressesWithLineBreaks: values.Get("addr_line_breaks") != "false",
+	}
+	if lookup.Text == "" {
+		return nil, nil, fmt.Errorf("no text provided (set text <value>)")
+	}
+	lookup.Aggressive, _ = strconv.ParseBool(values.Get("aggressive"))
+	lookup.AddressesPerLine, _ = strconv.Atoi(values.Get("addr_per_line"))
+	if err := clients.Extract().SendLookup(lookup); err != nil {
+		return nil, nil, err
+	}
+	var points []helps.Point
+	if lookup.Result != nil {
+		for _, address := range lookup.Result.Addresses {
+			for _, candidate := range address.APIOutput {
+				points = append(points, helps.Point{
+					Latitude:  candidate.Metadata.Latitude,
+					Longitude: candidate.Metadata.Longitude,
+					Name:      candidate.DeliveryLine1 + ", " + candidate.LastLine,
+				})
+			}
+		}
+	}
+	return lookup.Result, points, nil
+}
+
+func sendReverse(clients *clients, values url.Values) (interface{}, []helps.Point, error) {
+	latitude, latErr := strconv.ParseFloat(values.Get("latitude"), 64)
+	longitude, lonErr := strconv.ParseFloat(values.Get("longitude"), 64)
+	if latErr != nil || lonErr != nil {
+		return nil, nil, fmt.Errorf("numeric latitude and longitude required (set latitude <value>, set longitude <value>)")
+	}
+	lookup := &reverse.Lookup{Latitude: latitude, Longitude: longitude}
+	if err := clients.Reverse().SendLookup(lookup); err != nil {
+		return nil, nil, err
+	}
+	var points []helps.Point
+	for _, result := range lookup.Response.Results {
+		points = append(points, helps.Point{
+			Latitude:  result.Coordinate.Latitude,
+			Longitude: result.Coordinate.Longitude,
+			Name:      result.Address.Street + ", " + result.Address.City + ", " + result.Address.StateAbbreviation,
+		})
+	}
+	return lookup.Response.Results, points, nil
+}
+
+///////////////////
+
+// clients builds each API client on first use and keeps it for the rest of the session.
+type clients struct {
+	inputs *cli.Inputs
+
+	street        *street.Client
+	international *international.Client
+	zipcode       *zipcode.Client
+	autocomplete  *autocomplete.Client
+	extract       *extract.Client
+	reverse       *reverse.Client
+}
+
+func newClients(inputs *cli.Inputs) *clients {
+	return &clients{inputs: inputs}
+}
+
+func (this *clients) options(baseURLVariable string, licenses ...string) []wireup.Option {
+	return []wireup.Option{
+		wireup.CustomBaseURL(os.Getenv(baseURLVariable)),
+		wireup.SecretKeyCredential(this.inputs.AuthID, this.inputs.AuthToken),
+		wireup.WithLicenses(licenses...),
+	}
+}
+
+func (this *clients) Street() *street.Client {
+	if this.street == nil {
+		this.street = wireup.BuildUSStreetAPIClient(this.options("SMARTY_US_STREET_API", "us-core-cloud")...)
+	}
+	return this.street
+}
+
+func (this *clients) International() *international.Client {
+	if this.international == nil {
+		this.international = wireup.BuildInternationalStreetAPIClient(this.options("SMARTY_INTERNATIONAL_STREET_API")...)
+	}
+	return this.international
+}
+
+func (this *clients) ZIPCode() *zipcode.Client {
+	if this.zipcode == nil {
+		this.zipcode = wireup.BuildUSZIPCodeAPIClient(this.options("SMARTY_US_ZIPCODE_API")...)
+	}
+	return this.zipcode
+}
+
+func (this *clients) Autocomplete() *autocomplete.Client {
+	if this.autocomplete == nil {
+		this.autocomplete = wireup.BuildUSAutocompleteAPIClient(this.options("SMARTY_US_AUTOCOMPLETE_API")...)
+	}
+	return this.autocomplete
+}
+
+func (this *clients) Extract() *extract.Client {
+	if this.extract == nil {
+		this.extract = wireup.BuildUSExtractAPIClient(this.options("SMARTY_US_EXTRACT_API", "us-standard-cloud")...)
+	}
+	return this.extract
+}
+
+func (this *clients) Reverse() *reverse.Client {
+	if this.reverse == nil {
+		this.reverse = wireup.BuildUSReverseGeocodingAPIClient(this.options("SMARTY_US_REVERSE_GEO_API", "us-reverse-geocoding-cloud")...)
+	}
+	return this.reverse
+}
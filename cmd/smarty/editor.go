@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+// LineEditor reads lines from a terminal with basic editing: left/right (or ctrl-a/ctrl-e)
+// move the cursor, backspace deletes, and up/down step back and forth through the history.
+type LineEditor struct {
+	reader  *bufio.Reader
+	out     io.Writer
+	history *History
+}
+
+func NewLineEditor(in io.Reader, out io.Writer, history *History) *LineEditor {
+	return &LineEditor{reader: bufio.NewReader(in), out: out, history: history}
+}
+
+// ReadLine returns io.EOF for ctrl-d on an empty line. Ctrl-c abandons the line, returning it blank.
+func (this *LineEditor) ReadLine(prompt string) (string, error) {
+	restore, err := helps.RawTerminal()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		restore()
+		fmt.Fprintln(this.out)
+	}()
+
+	var line []rune
+	cursor := 0
+	recalled, draft := len(this.history.lines), "" // recalled is the history entry shown, or the draft when past the end
+	this.redraw(prompt, line, cursor)
+	for {
+		key, err := helps.ReadKey(this.reader)
+		if err != nil {
+			return "", err
+		}
+		switch key {
+		case helps.KeyEnter:
+			return string(line), nil
+		case helps.KeyInterrupt:
+			return "", nil
+		case helps.KeyEOF:
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+		case helps.KeyBackspace:
+			if cursor > 0 {
+				line = append(line[:cursor-1], line[cursor:]...)
+				cursor--
+			}
+		case helps.KeyLeft:
+			if cursor > 0 {
+				cursor--
+			}
+		case helps.KeyRight:
+			if cursor < len(line) {
+				cursor++
+			}
+		case helps.KeyHome:
+			cursor = 0
+		case helps.KeyEnd:
+			cursor = len(line)
+		case helps.KeyUp, helps.KeyDown:
+			if recalled == len(this.history.lines) {
+				draft = string(line)
+			}
+			if key == helps.KeyUp && recalled > 0 {
+				recalled--
+			} else if key == helps.KeyDown && recalled < len(this.history.lines) {
+				recalled++
+			}
+			if recalled == len(this.history.lines) {
+				line = []rune(draft)
+			} else {
+				line = []rune(this.history.lines[recalled])
+			}
+			cursor = len(line)
+		default:
+			if typed := []rune(key); len(typed) == 1 && typed[0] >= ' ' {
+				line = append(line[:cursor], append(typed, line[cursor:]...)...)
+				cursor++
+			}
+		}
+		this.redraw(prompt, line, cursor)
+	}
+}
+
+// redraw rewrites the whole line (clearing whatever is left of the previous one) and places the cursor.
+func (this *LineEditor) redraw(prompt string, line []rune, cursor int) {
+	fmt.Fprintf(this.out, "\r%s%s\033[K", prompt, string(line))
+	if back := len(line) - cursor; back > 0 {
+		fmt.Fprintf(this.out, "\033[%dD", back)
+	}
+}
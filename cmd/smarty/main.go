@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(log.Lmicroseconds)
+
+	if len(os.Args) < 2 {
+		log.Fatal("Usage: smarty <command> [flags] (commands: " + strings.Join(commandNames(), ", ") + ")")
+	}
+	command, found := commands[os.Args[1]]
+	if !found {
+		log.Fatal("Unrecognized command: ", os.Args[1])
+	}
+	os.Args = append(os.Args[:1], os.Args[2:]...) // so the command's flags parse normally
+	if err := command(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+var commands = map[string]func() error{
+	"repl": RunREPL,
+}
+
+func commandNames() (names []string) {
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
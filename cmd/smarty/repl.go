@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mdwhatcott/smarty-cli"
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+func RunREPL() error {
+	var historyPath string
+	home, _ := os.UserHomeDir()
+	flag.StringVar(&historyPath, "history", filepath.Join(home, ".smarty_history"), "The file that keeps the REPL history across sessions.")
+	inputs := cli.NewInputs()
+	inputs.ParseFlags()
+
+	session := NewSession(inputs, os.Stdout)
+	history := NewHistory(historyPath)
+	readLine := scanLines(os.Stdin, session.out)
+	if helps.IsTerminal(os.Stdin) {
+		readLine = NewLineEditor(os.Stdin, session.out, history).ReadLine
+	}
+
+	fmt.Fprintln(session.out, "Type 'help' for a list of commands.")
+	for {
+		line, err := readLine(fmt.Sprintf("smarty:%s> ", session.api))
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "!") {
+			recalled, err := history.Recall(line[1:])
+			if err != nil {
+				fmt.Fprintln(session.out, err)
+				continue
+			}
+			fmt.Fprintln(session.out, recalled)
+			line = recalled
+		}
+		if line == "" {
+			continue
+		}
+		history.Append(line)
+
+		if line == "history" {
+			history.List(session.out)
+			continue
+		}
+		if line == "quit" || line == "exit" {
+			return nil
+		}
+		if err := session.Execute(line); err != nil {
+			fmt.Fprintln(session.out, "Error:", err)
+		}
+	}
+}
+
+// scanLines reads plain lines (ie. when the input is piped rather than typed at a terminal).
+func scanLines(in io.Reader, out io.Writer) func(prompt string) (string, error) {
+	scanner := bufio.NewScanner(in)
+	return func(prompt string) (string, error) {
+		fmt.Fprint(out, prompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return scanner.Text(), nil
+	}
+}
+
+///////////////////
+
+// Session holds the state that survives between REPL commands:
+// the selected API, the fields set for each API, the clients built so far, and the last result.
+type Session struct {
+	*cli.Inputs
+	out io.Writer
+
+	api     string
+	fields  map[string]url.Values
+	clients *clients
+
+	lastResult interface{}
+	lastPoints []helps.Point
+}
+
+func NewSession(inputs *cli.Inputs, out io.Writer) *Session {
+	return &Session{
+		Inputs:  inputs,
+		out:     out,
+		api:     "street",
+		fields:  make(map[string]url.Values),
+		clients: newClients(inputs),
+	}
+}
+
+func (this *Session) Execute(line string) error {
+	words := strings.Fields(line)
+	command, args := words[0], words[1:]
+	switch command {
+	case "help":
+		fmt.Fprint(this.out, replHelp)
+	case "use":
+		return this.use(args)
+	case "set":
+		return this.set(strings.TrimSpace(line[len(command):]))
+	case "unset":
+		return this.unset(args)
+	case "clear":
+		delete(this.fields, this.api)
+	case "fields":
+		this.listFields()
+	case "send":
+		return this.send()
+	case "show":
+		return this.show(args)
+	default:
+		return fmt.Errorf("unrecognized command: %s (try 'help')", command)
+	}
+	return nil
+}
+
+func (this *Session) use(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: use <api> (choose from: %s)", strings.Join(apiNames(), ", "))
+	}
+	if _, found := apis[args[0]]; !found {
+		return fmt.Errorf("unrecognized api: %s (choose from: %s)", args[0], strings.Join(apiNames(), ", "))
+	}
+	this.api = args[0]
+	return nil
+}
+
+// set takes everything after the field name verbatim so that values may contain spaces.
+func (this *Session) set(arguments string) error {
+	split := strings.IndexAny(arguments, " \t")
+	if split < 0 {
+		return fmt.Errorf("usage: set <field> <value>")
+	}
+	field, value := arguments[:split], strings.TrimSpace(arguments[split:])
+	if this.fields[this.api] == nil {
+		this.fields[this.api] = make(url.Values)
+	}
+	this.fields[this.api].Set(field, value)
+	return nil
+}
+
+func (this *Session) unset(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: unset <field>")
+	}
+	this.fields[this.api].Del(args[0])
+	return nil
+}
+
+func (this *Session) listFields() {
+	values := this.fields[this.api]
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(this.out, "%s = %s\n", name, values.Get(name))
+	}
+}
+
+func (this *Session) send() error {
+	result, points, err := apis[this.api](this.clients, this.fields[this.api])
+	if err != nil {
+		return err
+	}
+	this.lastResult, this.lastPoints = result, points
+	return this.show(nil)
+}
+
+func (this *Session) show(args []string) error {
+	if this.lastResult == nil {
+		return fmt.Errorf("nothing sent yet")
+	}
+	format := helps.FormatJSON
+	if len(args) > 0 {
+		format = args[0]
+	}
+	if format == helps.FormatJSON {
+		fmt.Fprintln(this.out, helps.DumpJSON(this.lastResult))
+		return nil
+	}
+	dump, found := helps.PointFormats[format]
+	if !found {
+		return fmt.Errorf("unrecognized format: %s (choose from: %s)", format, helps.PointFormatNames())
+	}
+	var geocoded []helps.Point
+	for _, point := range this.lastPoints {
+		if point.Latitude != 0 || point.Longitude != 0 {
+			geocoded = append(geocoded, point)
+		}
+	}
+	fmt.Fprintln(this.out, dump(geocoded))
+	return nil
+}
+
+var replHelp = `Commands:
+  use <api>            Switch APIs (street, international, zipcode, autocomplete, extract, reverse).
+  set <field> <value>  Set a field for the current API, using the same names as the -query flag (ie. set city Provo).
+  unset <field>        Remove a field.
+  clear                Remove all fields for the current API.
+  fields               List the fields set for the current API.
+  send                 Send the current API's fields and show the result.
+  show [format]        Show the last result again (formats: ` + helps.PointFormatNames() + `).
+  history              List previous commands (recall one with !<number> or !!, or step through them with up/down).
+  quit                 Leave the REPL.
+`
+
+///////////////////
+
+// History keeps every entered line in a file so it is available to later sessions.
+type History struct {
+	path  string
+	lines []string
+}
+
+func NewHistory(path string) *History {
+	history := &History{path: path}
+	if file, err := os.Open(path); err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			history.lines = append(history.lines, scanner.Text())
+		}
+	}
+	return history
+}
+
+func (this *History) Append(line string) {
+	this.lines = append(this.lines, line)
+	file, err := os.OpenFile(this.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return // History is a convenience; a read-only home directory shouldn't end the session.
+	}
+	defer file.Close()
+	fmt.Fprintln(file, line)
+}
+
+func (this *History) List(out io.Writer) {
+	for i, line := range this.lines {
+		fmt.Fprintf(out, "%5d  %s\n", i+1, line)
+	}
+}
+
+// Recall finds an entry by number (!12) or, with "!" alone (!!), the most recent entry.
+func (this *History) Recall(reference string) (string, error) {
+	if reference == "!" && len(this.lines) > 0 {
+		return this.lines[len(this.lines)-1], nil
+	}
+	number, err := strconv.Atoi(reference)
+	if err != nil || number < 1 || number > len(this.lines) {
+		return "", fmt.Errorf("no such history entry: %s", reference)
+	}
+	return this.lines[number-1], nil
+}
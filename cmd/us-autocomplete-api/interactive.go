@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-autocomplete-api"
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+const debounce = 150 * time.Millisecond
+
+// TypeAhead is a terminal form that queries as the user types and verifies the chosen suggestion.
+type TypeAhead struct {
+	autocomplete *autocomplete.Client
+	street       *street.Client
+	template     autocomplete.Lookup
+	out          io.Writer
+
+	prefix      string
+	suggestions []*autocomplete.Suggestion
+	selected    int
+	status      string
+}
+
+func NewTypeAhead(autocompleteClient *autocomplete.Client, streetClient *street.Client, template autocomplete.Lookup) *TypeAhead {
+	return &TypeAhead{
+		autocomplete: autocompleteClient,
+		street:       streetClient,
+		template:     template,
+		out:          os.Stdout,
+	}
+}
+
+type suggestionResult struct {
+	prefix      string
+	suggestions []*autocomplete.Suggestion
+	err         error
+}
+
+func (this *TypeAhead) Run() error {
+	restore, err := helps.RawTerminal()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	keys := readKeys(os.Stdin)
+	results := make(chan suggestionResult)
+	done := make(chan struct{}) // closed on return, releasing any query still in flight
+	defer close(done)
+	var timer <-chan time.Time
+
+	this.render()
+	for {
+		select {
+		case key, open := <-keys:
+			if !open || key == helps.KeyInterrupt {
+				restore()
+				fmt.Fprintln(this.out)
+				return nil
+			}
+			if key == helps.KeyEnter {
+				if this.selected < len(this.suggestions) {
+					restore()
+					fmt.Fprintln(this.out)
+					return this.verify(this.suggestions[this.selected])
+				}
+				continue
+			}
+			if this.handle(key) {
+				timer = time.After(debounce)
+			}
+		case <-timer:
+			timer = nil
+			go this.query(this.prefix, results, done)
+		case result := <-results:
+			if result.prefix != this.prefix {
+				continue // stale: the user kept typing while this request was in flight
+			}
+			this.suggestions, this.selected, this.status = result.suggestions, 0, ""
+			if result.err != nil {
+				this.status = result.err.Error()
+			}
+		}
+		this.render()
+	}
+}
+
+// handle applies a keystroke, reporting whether the prefix changed.
+func (this *TypeAhead) handle(key string) bool {
+	switch key {
+	case helps.KeyUp:
+		if this.selected > 0 {
+			this.selected--
+		}
+	case helps.KeyDown:
+		if this.selected < len(this.suggestions)-1 {
+			this.selected++
+		}
+	case helps.KeyBackspace:
+		if runes := []rune(this.prefix); len(runes) > 0 {
+			this.prefix = string(runes[:len(runes)-1])
+			return true
+		}
+	default:
+		if len(key) > 0 && key[0] >= ' ' {
+			this.prefix += key
+			return true
+		}
+	}
+	return false
+}
+
+func (this *TypeAhead) query(prefix string, results chan<- suggestionResult, done <-chan struct{}) {
+	result := suggestionResult{prefix: prefix}
+	if strings.TrimSpace(prefix) != "" {
+		lookup := this.template
+		lookup.Prefix = prefix
+		lookup.Results = nil
+		result.err = this.autocomplete.SendLookup(&lookup)
+		result.suggestions = lookup.Results
+	}
+	select {
+	case results <- result:
+	case <-done:
+	}
+}
+
+func (this *TypeAhead) render() {
+	builder := new(strings.Builder)
+	builder.WriteString("\033[H\033[2J") // cursor home, clear screen
+	builder.WriteString("Address: " + this.prefix + "\r\n\r\n")
+	for i, suggestion := range this.suggestions {
+		if i == this.selected {
+			builder.WriteString("\033[7m> " + suggestion.Text + "\033[0m\r\n")
+		} else {
+			builder.WriteString("  " + suggestion.Text + "\r\n")
+		}
+	}
+	if this.status != "" {
+		builder.WriteString("\r\n" + this.status + "\r\n")
+	}
+	builder.WriteString("\r\n(type to search, up/down to choose, enter to verify, ctrl-c to quit)")
+	builder.WriteString(fmt.Sprintf("\033[1;%dH", len("Address: ")+len([]rune(this.prefix))+1))
+	fmt.Fprint(this.out, builder.String())
+}
+
+func (this *TypeAhead) verify(suggestion *autocomplete.Suggestion) error {
+	fmt.Fprintln(this.out, "\033[H\033[2J"+"Verifying:", suggestion.Text)
+	batch := street.NewBatch()
+	batch.Append(&street.Lookup{
+		Street:        suggestion.StreetLine,
+		City:          suggestion.City,
+		State:         suggestion.State,
+		MaxCandidates: 1,
+	})
+	if err := this.street.SendBatch(batch); err != nil {
+		return err
+	}
+	candidates := batch.Records()[0].Results
+	if len(candidates) == 0 {
+		fmt.Fprintln(this.out, "No candidates.")
+		return nil
+	}
+	candidate := candidates[0]
+	fmt.Fprintln(this.out)
+	fmt.Fprintln(this.out, candidate.DeliveryLine1)
+	if candidate.DeliveryLine2 != "" {
+		fmt.Fprintln(this.out, candidate.DeliveryLine2)
+	}
+	fmt.Fprintln(this.out, candidate.LastLine)
+	return nil
+}
+
+///////////////////
+
+// readKeys delivers one keystroke (a rune or an escape sequence) at a time.
+func readKeys(in io.Reader) <-chan string {
+	keys := make(chan string)
+	go func() {
+		defer close(keys)
+		reader := bufio.NewReader(in)
+		for {
+			key, err := helps.ReadKey(reader)
+			if err != nil {
+				return
+			}
+			keys <- key
+		}
+	}()
+	return keys
+}
@@ -7,7 +7,6 @@ import (
 	"net/url"
 	"os"
 	"strconv"
-	"strings"
 
 	"github.com/smartystreets/smartystreets-go-sdk/us-autocomplete-api"
 	"github.com/smartystreets/smartystreets-go-sdk/wireup"
@@ -21,6 +20,10 @@ func main() {
 
 	inputs := NewInputs()
 	inputs.Flags()
+	if inputs.interactive {
+		RunInteractive(inputs)
+		return
+	}
 	client := wireup.BuildUSAutocompleteAPIClient(
 		wireup.CustomBaseURL(inputs.baseURL),
 		wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
@@ -46,18 +49,22 @@ type Inputs struct {
 	prefix             string
 	suggestions        int
 	geolocatePrecision string
-	prefer             string
+	prefer             *cli.ListFlag
 	preferRatio        float64
-	cityFilter         string
-	stateFilter        string
+	cityFilter         *cli.ListFlag
+	stateFilter        *cli.ListFlag
+	interactive        bool
 
 	lookup *autocomplete.Lookup
 }
 
 func NewInputs() *Inputs {
 	return &Inputs{
-		Inputs: cli.NewInputs(),
-		lookup: new(autocomplete.Lookup),
+		Inputs:      cli.NewInputs(),
+		prefer:      cli.NewPreferenceListFlag(),
+		cityFilter:  cli.NewFilterListFlag(),
+		stateFilter: cli.NewFilterListFlag(),
+		lookup:      new(autocomplete.Lookup),
 	}
 }
 
@@ -65,11 +72,12 @@ func (this *Inputs) Flags() {
 	flag.StringVar(&this.baseURL, "baseURL", os.Getenv("SMARTY_US_AUTOCOMPLETE_API"), "The URL")
 	flag.StringVar(&this.prefix, "prefix", "", "The prefix field.")
 	flag.StringVar(&this.geolocatePrecision, "geolocate_precision", "city", "The geolocate_precision field (One of 'city', 'state', or 'none'. A value of 'None' will set the geolocate field to false).")
-	flag.StringVar(&this.prefer, "prefer", "", "The prefer field.")
+	flag.Var(this.prefer, "prefer", "The prefer field: 'CITY,ST' or 'ST' values separated by ';' (repeatable).")
 	flag.Float64Var(&this.preferRatio, "prefer_ratio", float64(1.0/3.0), "The prefer_ratio field.")
-	flag.StringVar(&this.cityFilter, "city_filter", "", "The city_filter field.")
-	flag.StringVar(&this.stateFilter, "state_filter", "", "The state_filter field.")
+	flag.Var(this.cityFilter, "city_filter", "The city_filter field: cities separated by ',' (repeatable; a city can't contain a comma).")
+	flag.Var(this.stateFilter, "state_filter", "The state_filter field: states separated by ',' (repeatable).")
 	flag.IntVar(&this.suggestions, "suggestions", 10, "The suggestions field.")
+	flag.BoolVar(&this.interactive, "interactive", false, "Query as you type in a terminal UI and verify the chosen suggestion with the US Street API (uses SMARTY_US_STREET_API as its URL).")
 	this.ParseFlags()
 }
 
@@ -95,11 +103,33 @@ func (this *Inputs) AssembleLookup() *autocomplete.Lookup {
 
 	return this.lookup
 }
+
+// RunInteractive uses the flag values (filters, preferences, etc...) for every lookup it sends.
+// The clients don't dump HTTP traffic since that would scramble the display.
+func RunInteractive(inputs *Inputs) {
+	inputs.assembleLookupFromFlags()
+	typeAhead := NewTypeAhead(
+		wireup.BuildUSAutocompleteAPIClient(
+			wireup.CustomBaseURL(inputs.baseURL),
+			wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
+		),
+		wireup.BuildUSStreetAPIClient(
+			wireup.CustomBaseURL(os.Getenv("SMARTY_US_STREET_API")),
+			wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
+			wireup.WithLicenses("us-core-cloud"),
+		),
+		*inputs.lookup,
+	)
+	if err := typeAhead.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
 func (this *Inputs) assembleLookupFromFlags() {
 	this.lookup.Prefix = this.prefix
-	this.lookup.CityFilter = strings.Split(this.cityFilter, ",")
-	this.lookup.StateFilter = strings.Split(this.stateFilter, ",")
-	this.lookup.Preferences = strings.Split(this.prefer, ";")
+	this.lookup.CityFilter = this.cityFilter.Values
+	this.lookup.StateFilter = this.stateFilter.Values
+	this.lookup.Preferences = this.prefer.Values
 	this.lookup.PreferRatio = this.preferRatio
 	if this.geolocatePrecision == "" {
 		this.lookup.Geolocation = autocomplete.GeolocateCity
@@ -112,9 +142,9 @@ func (this *Inputs) assembleLookupFromFlags() {
 }
 func (this *Inputs) assembleLookupFromQueryString(values url.Values) {
 	this.lookup.Prefix = values.Get("prefix")
-	this.lookup.CityFilter = strings.Split(values.Get("city_filter"), ",")
-	this.lookup.StateFilter = strings.Split(values.Get("state_filter"), ",")
-	this.lookup.Preferences = strings.Split(values.Get("prefer"), ";")
+	this.lookup.CityFilter = parseList(cli.NewFilterListFlag(), values["city_filter"])
+	this.lookup.StateFilter = parseList(cli.NewFilterListFlag(), values["state_filter"])
+	this.lookup.Preferences = parseList(cli.NewPreferenceListFlag(), values["prefer"])
 	this.lookup.PreferRatio, _ = strconv.ParseFloat(values.Get("prefer_ratio"), 64)
 	this.lookup.MaxSuggestions, _ = strconv.Atoi(values.Get("suggestions"))
 	precision := values.Get("geolocate_precision")
@@ -126,3 +156,10 @@ func (this *Inputs) assembleLookupFromQueryString(values url.Values) {
 		this.lookup.Geolocation = autocomplete.GeolocateNone
 	}
 }
+
+func parseList(list *cli.ListFlag, raw []string) []string {
+	if err := list.SetAll(raw); err != nil {
+		log.Fatal(err)
+	}
+	return list.Values
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-autocomplete-api"
+	"github.com/smartystreets/smartystreets-go-sdk/wireup"
+
+	"github.com/mdwhatcott/smarty-cli"
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+func main() {
+	log.SetFlags(log.Lmicroseconds)
+
+	inputs := NewInputs()
+	client := wireup.BuildUSAutocompleteAPIClient(
+		wireup.CustomBaseURL(inputs.baseURL),
+		wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
+	)
+	addresses := inputs.ReadAddresses()
+
+	var trials []*Trial
+	for i, address := range addresses {
+		trial, err := Simulate(client, inputs.Template(), address, inputs.minPrefix)
+		if err != nil {
+			log.Fatal(err)
+		}
+		trials = append(trials, trial)
+		log.Printf("[%d/%d] %s", i+1, len(addresses), trial)
+	}
+
+	if inputs.details {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "address\tlength\tkeystrokes\trank\trequests")
+		for _, trial := range trials {
+			fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%d\n", trial.Address.Typed(), trial.Length,
+				orMiss(trial.Keystrokes), orMiss(trial.Rank), trial.Requests)
+		}
+		_ = writer.Flush()
+		fmt.Println()
+	}
+	fmt.Print(Summarize(trials))
+}
+
+func orMiss(value int) string {
+	if value == 0 {
+		return "miss"
+	}
+	return fmt.Sprint(value)
+}
+
+///////////////////
+
+type Inputs struct {
+	*cli.Inputs
+
+	baseURL string
+
+	input              string
+	details            bool
+	minPrefix          int
+	suggestions        int
+	geolocatePrecision string
+	prefer             *cli.ListFlag
+	preferRatio        float64
+	cityFilter         *cli.ListFlag
+	stateFilter        *cli.ListFlag
+}
+
+func NewInputs() *Inputs {
+	this := &Inputs{
+		Inputs:      cli.NewInputs(),
+		prefer:      cli.NewPreferenceListFlag(),
+		cityFilter:  cli.NewFilterListFlag(),
+		stateFilter: cli.NewFilterListFlag(),
+	}
+	this.flags()
+	return this
+}
+
+func (this *Inputs) flags() {
+	flag.StringVar(&this.baseURL, "baseURL", os.Getenv("SMARTY_US_AUTOCOMPLETE_API"), "The URL")
+	flag.StringVar(&this.input, "input", "-", "A CSV file of known addresses with street, city and state columns. Defaults to stdin.")
+	flag.BoolVar(&this.details, "details", false, "List the outcome for every address before the summary.")
+	flag.IntVar(&this.minPrefix, "min-prefix", 1, "How many characters to type before the first request is sent.")
+	flag.StringVar(&this.geolocatePrecision, "geolocate_precision", "city", "The geolocate_precision field (One of 'city', 'state', or 'none').")
+	flag.Var(this.prefer, "prefer", "The prefer field: 'CITY,ST' or 'ST' values separated by ';' (repeatable).")
+	flag.Float64Var(&this.preferRatio, "prefer_ratio", float64(1.0/3.0), "The prefer_ratio field.")
+	flag.Var(this.cityFilter, "city_filter", "The city_filter field: cities separated by ',' (repeatable; a city can't contain a comma).")
+	flag.Var(this.stateFilter, "state_filter", "The state_filter field: states separated by ',' (repeatable).")
+	flag.IntVar(&this.suggestions, "suggestions", 10, "The suggestions field.")
+	this.ParseFlags()
+}
+
+// Template holds the settings under evaluation; only the prefix varies between requests.
+func (this *Inputs) Template() autocomplete.Lookup {
+	lookup := autocomplete.Lookup{
+		MaxSuggestions: this.suggestions,
+		PreferRatio:    this.preferRatio,
+		CityFilter:     this.cityFilter.Values,
+		StateFilter:    this.stateFilter.Values,
+		Preferences:    this.prefer.Values,
+	}
+	switch this.geolocatePrecision {
+	case "city", "":
+		lookup.Geolocation = autocomplete.GeolocateCity
+	case "state":
+		lookup.Geolocation = autocomplete.GeolocateState
+	case "none":
+		lookup.Geolocation = autocomplete.GeolocateNone
+	default:
+		log.Fatal("Unrecognized -geolocate_precision value:", this.geolocatePrecision)
+	}
+	return lookup
+}
+
+func (this *Inputs) ReadAddresses() (addresses []Address) {
+	table, err := helps.ReadTableFile(this.input, "street", "city", "state")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, row := range table.Rows {
+		addresses = append(addresses, Address{
+			Street: table.Get(row, "street"),
+			City:   table.Get(row, "city"),
+			State:  table.Get(row, "state"),
+		})
+	}
+	return addresses
+}
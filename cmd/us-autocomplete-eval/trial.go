@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-autocomplete-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+type Address struct {
+	Street string
+	City   string
+	State  string
+}
+
+// Typed is the text entered into the form, one character at a time.
+func (this Address) Typed() string {
+	return strings.Join(strings.Fields(this.Street+" "+this.City+" "+this.State), " ")
+}
+
+func (this Address) Matches(suggestion *autocomplete.Suggestion) bool {
+	return normalize(this.Street) == normalize(suggestion.StreetLine) &&
+		normalize(this.City) == normalize(suggestion.City) &&
+		normalize(this.State) == normalize(suggestion.State)
+}
+
+// normalize ignores case, punctuation and spacing, but not abbreviations,
+// so the known addresses should be written in standardized (USPS) form.
+func normalize(value string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToUpper(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+type Trial struct {
+	Address    Address
+	Length     int // characters in the fully typed address
+	Keystrokes int // characters typed before the address was suggested (0 if it never was)
+	Rank       int // 1-based position of the address among the suggestions (0 if never suggested)
+	Requests   int
+}
+
+func (this *Trial) String() string {
+	if this.Rank == 0 {
+		return fmt.Sprintf("%s: never suggested (%d requests)", this.Address.Typed(), this.Requests)
+	}
+	return fmt.Sprintf("%s: rank %d after %d of %d keystrokes", this.Address.Typed(), this.Rank, this.Keystrokes, this.Length)
+}
+
+// Simulate sends one request per keystroke until the address appears among the suggestions.
+func Simulate(client *autocomplete.Client, template autocomplete.Lookup, address Address, minPrefix int) (*Trial, error) {
+	typed := []rune(address.Typed())
+	trial := &Trial{Address: address, Length: len(typed)}
+	if minPrefix < 1 {
+		minPrefix = 1
+	}
+	for keystrokes := minPrefix; keystrokes <= len(typed); keystrokes++ {
+		if unicode.IsSpace(typed[keystrokes-1]) {
+			continue // the suggestions for "123 main " are the same as for "123 main"
+		}
+		lookup := template
+		lookup.Prefix = string(typed[:keystrokes])
+		lookup.Results = nil
+		if err := client.SendLookup(&lookup); err != nil {
+			return nil, err
+		}
+		trial.Requests++
+		for rank, suggestion := range lookup.Results {
+			if address.Matches(suggestion) {
+				trial.Keystrokes, trial.Rank = keystrokes, rank+1
+				return trial, nil
+			}
+		}
+	}
+	return trial, nil
+}
+
+func Summarize(trials []*Trial) string {
+	var keystrokes, fractions, ranks []float64
+	requests := 0
+	for _, trial := range trials {
+		requests += trial.Requests
+		if trial.Rank == 0 {
+			continue
+		}
+		keystrokes = append(keystrokes, float64(trial.Keystrokes))
+		fractions = append(fractions, 100*float64(trial.Keystrokes)/float64(trial.Length))
+		ranks = append(ranks, float64(trial.Rank))
+	}
+
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "addresses: %d\n", len(trials))
+	fmt.Fprintf(builder, "suggested: %d\n", len(keystrokes))
+	fmt.Fprintf(builder, "missed:    %d\n", len(trials)-len(keystrokes))
+	fmt.Fprintf(builder, "requests:  %d\n", requests)
+	if len(keystrokes) == 0 {
+		return builder.String()
+	}
+	fmt.Fprintln(builder)
+	fmt.Fprintln(builder, "percentile  keystrokes  typed%  rank")
+	for _, p := range []float64{50, 75, 90, 95, 99, 100} {
+		fmt.Fprintf(builder, "p%-9v  %10v  %6.1f  %4v\n", p,
+			helps.Percentile(keystrokes, p), helps.Percentile(fractions, p), helps.Percentile(ranks, p))
+	}
+	return builder.String()
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-extract-api"
+)
+
+// overlapLines are repeated at the start of each chunk (after the first) so that an address
+// that straddles a chunk boundary is found whole in at least one chunk.
+const overlapLines = 3
+
+// Chunk is a slice of a document small enough to send in one lookup.
+type Chunk struct {
+	Text   string
+	Line   int // newlines in the document before the chunk
+	Offset int // characters in the document before the chunk
+}
+
+// SplitChunks divides text into chunks of at most maxBytes, breaking after a blank line
+// (a paragraph) when one is available in the back half of a chunk, otherwise after a line.
+// A line longer than maxBytes on its own is broken wherever it must be.
+func SplitChunks(text string, maxBytes int) (chunks []Chunk) {
+	if len(text) <= maxBytes {
+		return []Chunk{{Text: text}}
+	}
+	segments := splitSegments(text, maxBytes)
+	for start := 0; start < len(segments); {
+		end, size := start, 0
+		for end < len(segments) && size+len(segments[end].Text) <= maxBytes {
+			size += len(segments[end].Text)
+			end++
+		}
+		if end == start { // a single character over maxBytes
+			end++
+		}
+		if end < len(segments) {
+			for paragraph := end - 1; paragraph > start+(end-start)/2; paragraph-- {
+				if strings.TrimSpace(segments[paragraph].Text) == "" {
+					end = paragraph + 1
+					break
+				}
+			}
+		}
+		chunk := segments[start]
+		for _, segment := range segments[start+1 : end] {
+			chunk.Text += segment.Text
+		}
+		chunks = append(chunks, chunk)
+		if end == len(segments) {
+			break
+		}
+		if end-start <= overlapLines { // too short to overlap without stalling
+			start = end
+		} else {
+			start = end - overlapLines
+		}
+	}
+	return chunks
+}
+
+// splitSegments breaks text into lines, and any line over maxBytes into pieces.
+func splitSegments(text string, maxBytes int) (segments []Chunk) {
+	line, offset := 0, 0
+	for _, text := range strings.SplitAfter(text, "\n") {
+		for len(text) > maxBytes {
+			cut := maxBytes
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+			if cut == 0 { // maxBytes is smaller than the first character, so take it whole
+				_, cut = utf8.DecodeRuneInString(text)
+			}
+			segments = append(segments, Chunk{Text: text[:cut], Line: line, Offset: offset})
+			offset += utf8.RuneCountInString(text[:cut])
+			text = text[cut:]
+		}
+		if text != "" {
+			segments = append(segments, Chunk{Text: text, Line: line, Offset: offset})
+		}
+		offset += utf8.RuneCountInString(text)
+		line++
+	}
+	return segments
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// MergeChunks combines the results of each chunk into one result for the whole text,
+// remapping line numbers and offsets and dropping the duplicate (or partial) addresses
+// found in the overlap between chunks.
+func MergeChunks(text string, chunks []Chunk, results []*extract.Result) *extract.Result {
+	merged := &extract.Result{}
+	var addresses []*extract.ExtractedAddress
+	for i, result := range results {
+		if result == nil {
+			continue
+		}
+		merged.Metadata.Unicode = merged.Metadata.Unicode || result.Metadata.Unicode
+		for _, address := range result.Addresses {
+			remapped := *address
+			remapped.Line += chunks[i].Line
+			remapped.Start += chunks[i].Offset
+			remapped.End += chunks[i].Offset
+			addresses = append(addresses, &remapped)
+		}
+	}
+
+	// Longest first among those starting together, so that duplicates and fragments follow what contains them.
+	sort.SliceStable(addresses, func(i, j int) bool {
+		if addresses[i].Start != addresses[j].Start {
+			return addresses[i].Start < addresses[j].Start
+		}
+		return addresses[i].End > addresses[j].End
+	})
+	furthest := -1
+	for _, address := range addresses {
+		if address.End <= furthest {
+			continue
+		}
+		furthest = address.End
+		merged.Addresses = append(merged.Addresses, address)
+		if address.Verified {
+			merged.Metadata.VerifiedCount++
+		}
+	}
+
+	merged.Metadata.AddressCount = len(merged.Addresses)
+	merged.Metadata.Lines = strings.Count(text, "\n") + 1
+	merged.Metadata.Bytes = len(text)
+	merged.Metadata.CharacterCount = utf8.RuneCountInString(text)
+	return merged
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-extract-api"
+)
+
+func TestSplitChunksWhole(t *testing.T) {
+	chunks := SplitChunks("short\ntext", 100)
+	if expected := []Chunk{{Text: "short\ntext"}}; !reflect.DeepEqual(chunks, expected) {
+		t.Errorf("got %+v, want %+v", chunks, expected)
+	}
+}
+
+func TestSplitChunksOverlap(t *testing.T) {
+	text := "line0\nline1\nline2\nline3\nline4\nline5\nline6\nline7\n" // 6 bytes per line
+	chunks := SplitChunks(text, 30)
+	expected := []Chunk{
+		{Text: "line0\nline1\nline2\nline3\nline4\n", Line: 0, Offset: 0},
+		{Text: "line2\nline3\nline4\nline5\nline6\n", Line: 2, Offset: 12},
+		{Text: "line4\nline5\nline6\nline7\n", Line: 4, Offset: 24},
+	}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Errorf("got %+v, want %+v", chunks, expected)
+	}
+}
+
+func TestSplitChunksWithoutRoomToOverlap(t *testing.T) {
+	text := "line0\nline1\nline2\nline3\n"
+	chunks := SplitChunks(text, 12)
+	expected := []Chunk{
+		{Text: "line0\nline1\n", Line: 0, Offset: 0},
+		{Text: "line2\nline3\n", Line: 2, Offset: 12},
+	}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Errorf("got %+v, want %+v", chunks, expected)
+	}
+}
+
+func TestSplitChunksLongSegment(t *testing.T) {
+	text := "ab" + strings.Repeat("é", 4) + "\nc" // é is two bytes
+	chunks := SplitChunks(text, 5)
+	for _, chunk := range chunks {
+		if len(chunk.Text) > 5 {
+			t.Errorf("chunk over max bytes: %q", chunk.Text)
+		}
+	}
+	expected := []Chunk{
+		{Text: "abé", Line: 0, Offset: 0},
+		{Text: "éé", Line: 0, Offset: 3},
+		{Text: "é\nc", Line: 0, Offset: 5},
+	}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Errorf("got %+v, want %+v", chunks, expected)
+	}
+}
+
+func TestSplitChunksCharacterOverMaxBytes(t *testing.T) {
+	chunks := SplitChunks("éé", 1)
+	expected := []Chunk{{Text: "é", Offset: 0}, {Text: "é", Offset: 1}}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Errorf("got %+v, want %+v", chunks, expected)
+	}
+}
+
+func TestMergeChunks(t *testing.T) {
+	text := "line0\nline1\nline2\nline3\nline4\nline5\nline6\nline7\n"
+	chunks := SplitChunks(text, 30)
+	address := func(line, start, end int) *extract.ExtractedAddress {
+		return &extract.ExtractedAddress{Line: line, Start: start, End: end, Verified: true}
+	}
+	results := []*extract.Result{
+		{Addresses: []*extract.ExtractedAddress{address(0, 0, 5), address(3, 18, 29)}},  // line3-4, found again below
+		{Addresses: []*extract.ExtractedAddress{address(1, 6, 17), address(3, 18, 23)}}, // line3-4 again, and a fragment of line5-6
+		{Addresses: []*extract.ExtractedAddress{address(1, 6, 17)}},                     // line5-6 whole
+	}
+
+	merged := MergeChunks(text, chunks, results)
+
+	expected := []*extract.ExtractedAddress{address(0, 0, 5), address(3, 18, 29), address(5, 30, 41)}
+	if !reflect.DeepEqual(merged.Addresses, expected) {
+		for _, address := range merged.Addresses {
+			t.Logf("%+v", *address)
+		}
+		t.Errorf("got %d addresses, want %d", len(merged.Addresses), len(expected))
+	}
+	if merged.Metadata.AddressCount != 3 || merged.Metadata.VerifiedCount != 3 {
+		t.Errorf("got %d addresses (%d verified), want 3 (3 verified)", merged.Metadata.AddressCount, merged.Metadata.VerifiedCount)
+	}
+	if merged.Metadata.Lines != 9 || merged.Metadata.Bytes != len(text) {
+		t.Errorf("got %d lines of %d bytes, want 9 lines of %d bytes", merged.Metadata.Lines, merged.Metadata.Bytes, len(text))
+	}
+}
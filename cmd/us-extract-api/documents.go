@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-extract-api"
+)
+
+// Document is one body of text submitted as its own lookup.
+type Document struct {
+	Source string // the file name, "stdin", or blank for text given by flag or query string
+	Text   string
+	Result *extract.Result
+}
+
+// Extract sends the document's text using the settings (aggressive, html, etc...) of the template.
+// Text over maxBytes is sent in chunks and the results merged.
+func (this *Document) Extract(client *extract.Client, template extract.Lookup, maxBytes int) error {
+	chunks := SplitChunks(this.Text, maxBytes)
+	var results []*extract.Result
+	for _, chunk := range chunks {
+		lookup := template
+		lookup.Text = chunk.Text
+		lookup.Result = nil
+		if err := client.SendLookup(&lookup); err != nil {
+			return err
+		}
+		results = append(results, lookup.Result)
+	}
+	if len(chunks) == 1 {
+		this.Result = results[0]
+	} else {
+		this.Result = MergeChunks(this.Text, chunks, results)
+	}
+	return nil
+}
+
+// ReadDocuments reads each path: "-" is stdin, a file is read regardless of its extension,
+// and a directory is searched recursively for files with one of the extensions.
+func ReadDocuments(paths []string, extensions []string) (documents []*Document, err error) {
+	for _, path := range paths {
+		if path == "-" {
+			content, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				return nil, err
+			}
+			documents = append(documents, &Document{Source: "stdin", Text: string(content)})
+			continue
+		}
+		err = filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || (name != path && !hasExtension(name, extensions)) {
+				return nil
+			}
+			content, err := ioutil.ReadFile(name)
+			if err != nil {
+				return err
+			}
+			documents = append(documents, &Document{Source: name, Text: string(content)})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return documents, nil
+}
+
+func hasExtension(name string, extensions []string) bool {
+	extension := strings.ToLower(filepath.Ext(name))
+	for _, candidate := range extensions {
+		if extension == strings.ToLower("."+strings.TrimPrefix(candidate, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// TaggedAddress is an extracted address labeled with the document it was found in.
+type TaggedAddress struct {
+	Source string `json:"source"`
+	*extract.ExtractedAddress
+}
+
+func TagAddresses(documents []*Document) (tagged []TaggedAddress) {
+	tagged = []TaggedAddress{}
+	for _, document := range documents {
+		if document.Result == nil {
+			continue
+		}
+		for _, address := range document.Result.Addresses {
+			tagged = append(tagged, TaggedAddress{Source: document.Source, ExtractedAddress: address})
+		}
+	}
+	return tagged
+}
+
+// PrintDocuments writes each rendered document to stdout, preceded by
+// its name when there is more than one.
+func PrintDocuments(documents []*Document, render func(*Document) string) {
+	for i, document := range documents {
+		if len(documents) > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("==> %s <==\n", document.Source)
+		}
+		fmt.Print(render(document))
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-extract-api"
+)
+
+// Label is an address span marked by hand in a corpus document.
+type Label struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// ParseLabels removes the open/close markers around each labeled address
+// from the document's text and returns the spans they enclosed.
+func ParseLabels(document *Document, open, close string) (labels []Label, err error) {
+	builder := new(strings.Builder)
+	text := document.Text
+	for {
+		start := strings.Index(text, open)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start+len(open):], close)
+		if end < 0 {
+			return nil, fmt.Errorf("%s: unclosed label: %q", document.Source, abbreviate(text[start:]))
+		}
+		builder.WriteString(text[:start])
+		labeled := text[start+len(open) : start+len(open)+end]
+		offset := utf8.RuneCountInString(builder.String())
+		labels = append(labels, Label{Start: offset, End: offset + utf8.RuneCountInString(labeled), Text: labeled})
+		builder.WriteString(labeled)
+		text = text[start+len(open)+end+len(close):]
+	}
+	builder.WriteString(text)
+	document.Text = builder.String()
+	return labels, nil
+}
+
+func abbreviate(text string) string {
+	if runes := []rune(text); len(runes) > 40 {
+		return string(runes[:40]) + "..."
+	}
+	return text
+}
+
+// ApplyOptions overrides the template with the extract settings named in the query string
+// (aggressive, addr_line_breaks, addr_per_line, html), leaving any others as they were.
+func ApplyOptions(template extract.Lookup, options string) (extract.Lookup, error) {
+	values, err := url.ParseQuery(options)
+	if err != nil {
+		return template, err
+	}
+	for key := range values {
+		value := values.Get(key)
+		switch key {
+		case "aggressive":
+			template.Aggressive, err = strconv.ParseBool(value)
+		case "addr_line_breaks":
+			template.AddressesWithLineBreaks, err = strconv.ParseBool(value)
+		case "addr_per_line":
+			template.AddressesPerLine, err = strconv.Atoi(value)
+		case "html":
+			template.HTML = extract.HTMLPayload(value)
+		default:
+			return template, fmt.Errorf("unrecognized option: %s", key)
+		}
+		if err != nil {
+			return template, fmt.Errorf("option %s: %w", key, err)
+		}
+	}
+	return template, nil
+}
+
+///////////////////
+
+// Evaluation tallies how the addresses extracted under one set of options compare to the labels.
+type Evaluation struct {
+	Options        string
+	TruePositives  int
+	FalsePositives int
+	FalseNegatives int
+	Misses         map[string][]string // labeled addresses that weren't found, by document
+	FalseAlarms    map[string][]string // addresses found that weren't labeled, by document
+	sources        []string
+}
+
+func NewEvaluation(options string) *Evaluation {
+	return &Evaluation{
+		Options:     options,
+		Misses:      make(map[string][]string),
+		FalseAlarms: make(map[string][]string),
+	}
+}
+
+// Score matches each extracted span to at most one label, in order, when
+// they overlap by at least the minimum fraction of their combined extent.
+func (this *Evaluation) Score(source string, labels []Label, spans []Span, minimumOverlap float64) {
+	this.sources = append(this.sources, source)
+	matched := make([]bool, len(labels))
+	for _, span := range spans {
+		found := false
+		for i, label := range labels {
+			if !matched[i] && overlap(label, span) >= minimumOverlap {
+				matched[i], found = true, true
+				break
+			}
+		}
+		if found {
+			this.TruePositives++
+		} else {
+			this.FalsePositives++
+			this.FalseAlarms[source] = append(this.FalseAlarms[source], span.Address.Text)
+		}
+	}
+	for i, label := range labels {
+		if !matched[i] {
+			this.FalseNegatives++
+			this.Misses[source] = append(this.Misses[source], label.Text)
+		}
+	}
+}
+
+// overlap is the intersection over the union of the two spans.
+func overlap(label Label, span Span) float64 {
+	start, end := label.Start, label.End
+	if span.Start > start {
+		start = span.Start
+	}
+	if span.End < end {
+		end = span.End
+	}
+	if end <= start {
+		return 0
+	}
+	intersection := end - start
+	union := (label.End - label.Start) + (span.End - span.Start) - intersection
+	return float64(intersection) / float64(union)
+}
+
+func (this *Evaluation) Precision() float64 {
+	return ratio(this.TruePositives, this.TruePositives+this.FalsePositives)
+}
+
+func (this *Evaluation) Recall() float64 {
+	return ratio(this.TruePositives, this.TruePositives+this.FalseNegatives)
+}
+
+func (this *Evaluation) F1() float64 {
+	precision, recall := this.Precision(), this.Recall()
+	if precision+recall == 0 {
+		return 0
+	}
+	return 2 * precision * recall / (precision + recall)
+}
+
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+func (this *Evaluation) String() string {
+	builder := new(strings.Builder)
+	fmt.Fprintf(builder, "options: %s\n", describeOptions(this.Options))
+	fmt.Fprintf(builder, "  true positives: %d  false positives: %d  false negatives: %d\n",
+		this.TruePositives, this.FalsePositives, this.FalseNegatives)
+	fmt.Fprintf(builder, "  precision: %.3f  recall: %.3f  f1: %.3f\n", this.Precision(), this.Recall(), this.F1())
+	for _, source := range this.sources {
+		for _, text := range this.Misses[source] {
+			fmt.Fprintf(builder, "  missed in %s: %q\n", source, text)
+		}
+		for _, text := range this.FalseAlarms[source] {
+			fmt.Fprintf(builder, "  unlabeled in %s: %q\n", source, text)
+		}
+	}
+	return builder.String()
+}
+
+func describeOptions(options string) string {
+	if options == "" {
+		return "(defaults)"
+	}
+	return options
+}
+
+func SummarizeEvaluations(evaluations []*Evaluation) string {
+	builder := new(strings.Builder)
+	writer := tabwriter.NewWriter(builder, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "options\tprecision\trecall\tf1\tmisses")
+	for _, evaluation := range evaluations {
+		fmt.Fprintf(writer, "%s\t%.3f\t%.3f\t%.3f\t%d\n", describeOptions(evaluation.Options),
+			evaluation.Precision(), evaluation.Recall(), evaluation.F1(), evaluation.FalseNegatives)
+	}
+	_ = writer.Flush()
+	return builder.String()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-extract-api"
+)
+
+func TestParseLabels(t *testing.T) {
+	document := &Document{Source: "corpus.txt", Text: "Café at [[1 Main St]], or [[2 Elm St]]."}
+	labels, err := ParseLabels(document, "[[", "]]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Café at 1 Main St, or 2 Elm St."; document.Text != expected {
+		t.Errorf("text: got %q, want %q", document.Text, expected)
+	}
+	expected := []Label{{Start: 8, End: 17, Text: "1 Main St"}, {Start: 22, End: 30, Text: "2 Elm St"}}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("labels: got %+v, want %+v", labels, expected)
+	}
+}
+
+func TestParseLabelsUnclosed(t *testing.T) {
+	document := &Document{Source: "corpus.txt", Text: "[[1 Main St]] and [[2 Elm St"}
+	if _, err := ParseLabels(document, "[[", "]]"); err == nil {
+		t.Error("expected an error for an unclosed label")
+	}
+}
+
+func span(start, end int) Span {
+	return Span{Start: start, End: end, Address: &extract.ExtractedAddress{}}
+}
+
+func TestEvaluationScore(t *testing.T) {
+	labels := []Label{{Start: 0, End: 10}, {Start: 20, End: 30}, {Start: 40, End: 50}}
+	spans := []Span{span(0, 10), span(21, 30), span(60, 70)}
+
+	evaluation := NewEvaluation("")
+	evaluation.Score("corpus.txt", labels, spans, 0.5)
+
+	if evaluation.TruePositives != 2 || evaluation.FalsePositives != 1 || evaluation.FalseNegatives != 1 {
+		t.Errorf("got %d/%d/%d (true positives/false positives/false negatives), want 2/1/1",
+			evaluation.TruePositives, evaluation.FalsePositives, evaluation.FalseNegatives)
+	}
+	assertClose(t, "precision", evaluation.Precision(), 2.0/3)
+	assertClose(t, "recall", evaluation.Recall(), 2.0/3)
+	assertClose(t, "f1", evaluation.F1(), 2.0/3)
+}
+
+func TestEvaluationScoreSpanOverlappingTwoLabels(t *testing.T) {
+	labels := []Label{{Start: 0, End: 10}, {Start: 10, End: 20}}
+	spans := []Span{span(5, 15)} // a third of its combined extent with each label
+
+	lenient := NewEvaluation("")
+	lenient.Score("corpus.txt", labels, spans, 0.3)
+	if lenient.TruePositives != 1 || lenient.FalsePositives != 0 || lenient.FalseNegatives != 1 {
+		t.Errorf("lenient: got %d/%d/%d, want 1/0/1", lenient.TruePositives, lenient.FalsePositives, lenient.FalseNegatives)
+	}
+
+	strict := NewEvaluation("")
+	strict.Score("corpus.txt", labels, spans, 0.5)
+	if strict.TruePositives != 0 || strict.FalsePositives != 1 || strict.FalseNegatives != 2 {
+		t.Errorf("strict: got %d/%d/%d, want 0/1/2", strict.TruePositives, strict.FalsePositives, strict.FalseNegatives)
+	}
+}
+
+func TestEvaluationEmpty(t *testing.T) {
+	evaluation := NewEvaluation("")
+	evaluation.Score("corpus.txt", nil, nil, 0.5)
+	assertClose(t, "precision", evaluation.Precision(), 0)
+	assertClose(t, "recall", evaluation.Recall(), 0)
+	assertClose(t, "f1", evaluation.F1(), 0)
+}
+
+func TestOverlap(t *testing.T) {
+	for _, test := range []struct {
+		label    Label
+		span     Span
+		expected float64
+	}{
+		{label: Label{Start: 0, End: 10}, span: span(0, 10), expected: 1},
+		{label: Label{Start: 0, End: 10}, span: span(5, 15), expected: 1.0 / 3},
+		{label: Label{Start: 0, End: 10}, span: span(2, 6), expected: 0.4},
+		{label: Label{Start: 0, End: 10}, span: span(10, 20), expected: 0},
+		{label: Label{Start: 10, End: 20}, span: span(0, 5), expected: 0},
+	} {
+		assertClose(t, "overlap", overlap(test.label, test.span), test.expected)
+	}
+}
+
+func assertClose(t *testing.T, name string, actual, expected float64) {
+	t.Helper()
+	if math.Abs(actual-expected) > 1e-9 {
+		t.Errorf("%s: got %f, want %f", name, actual, expected)
+	}
+}
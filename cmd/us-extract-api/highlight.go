@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"strings"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-extract-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+const (
+	highlightTerminal = "terminal"
+	highlightHTML     = "html"
+)
+
+// Highlight numbers each address in the text (in green when verified, red otherwise)
+// and follows the text with a legend of the standardized results.
+func Highlight(document *Document, colored bool) string {
+	spans := Spans(document.Text, document.Result)
+	builder := new(strings.Builder)
+	builder.WriteString(Replace(document.Text, spans, func(i int, span Span) string {
+		return helps.Colorize(fmt.Sprintf("[%d: %s]", i+1, span.Address.Text), highlightColor(span.Address), colored)
+	}))
+	if !strings.HasSuffix(document.Text, "\n") {
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\n")
+	for i, span := range spans {
+		line := fmt.Sprintf("[%d] %-10s %s", i+1, verification(span.Address), standardized(span.Address))
+		builder.WriteString(helps.Colorize(line, highlightColor(span.Address), colored) + "\n")
+	}
+	return builder.String()
+}
+
+func highlightColor(address *extract.ExtractedAddress) string {
+	if address.Verified {
+		return helps.ColorGreen
+	}
+	return helps.ColorRed
+}
+
+func verification(address *extract.ExtractedAddress) string {
+	if address.Verified {
+		return "verified"
+	}
+	return "unverified"
+}
+
+// standardized is the first candidate's address on one line, or the address as found if unverified.
+func standardized(address *extract.ExtractedAddress) string {
+	if !address.Verified || len(address.APIOutput) == 0 {
+		return strings.Join(strings.Fields(address.Text), " ")
+	}
+	candidate := address.APIOutput[0]
+	lines := []string{candidate.DeliveryLine1}
+	if candidate.DeliveryLine2 != "" {
+		lines = append(lines, candidate.DeliveryLine2)
+	}
+	return strings.Join(append(lines, candidate.LastLine), ", ")
+}
+
+///////////////////
+
+type htmlDocument struct {
+	Source   string
+	Segments []htmlSegment
+	Legend   []htmlSegment
+}
+
+type htmlSegment struct {
+	Text     string
+	Number   int // zero for the text between addresses
+	ID       string
+	Verified bool
+}
+
+// HighlightHTML renders every document as a page section with the text on the left
+// and the legend of standardized results on the right.
+func HighlightHTML(documents []*Document) string {
+	var pages []htmlDocument
+	for d, document := range documents {
+		page := htmlDocument{Source: document.Source}
+		runes := []rune(document.Text)
+		position := 0
+		for i, span := range Spans(document.Text, document.Result) {
+			id := fmt.Sprintf("document-%d-address-%d", d+1, i+1)
+			page.Segments = append(page.Segments,
+				htmlSegment{Text: string(runes[position:span.Start])},
+				htmlSegment{Text: span.Address.Text, Number: i + 1, ID: id, Verified: span.Address.Verified},
+			)
+			page.Legend = append(page.Legend, htmlSegment{Text: standardized(span.Address), Number: i + 1, ID: id, Verified: span.Address.Verified})
+			position = span.End
+		}
+		page.Segments = append(page.Segments, htmlSegment{Text: string(runes[position:])})
+		pages = append(pages, page)
+	}
+	builder := new(strings.Builder)
+	if err := highlightTemplate.Execute(builder, pages); err != nil {
+		log.Panic(err)
+	}
+	return builder.String()
+}
+
+var highlightTemplate = template.Must(template.New("highlight").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>US Extract API Results</title>
+<style>
+  body { font-family: sans-serif; margin: 2em; }
+  section { display: flex; gap: 2em; margin-bottom: 3em; }
+  pre { flex: 2; white-space: pre-wrap; background: #f8f8f8; padding: 1em; }
+  ol { flex: 1; }
+  mark.verified { background: #c8f0c8; }
+  mark.unverified { background: #f8c8c8; }
+  sup { font-weight: bold; margin-right: 0.2em; }
+</style>
+</head>
+<body>
+{{range .}}<h2>{{if .Source}}{{.Source}}{{else}}Text{{end}}</h2>
+<section>
+<pre>{{range .Segments}}{{if .Number}}<mark id="{{.ID}}" class="{{if .Verified}}verified{{else}}unverified{{end}}"><sup>{{.Number}}</sup>{{.Text}}</mark>{{else}}{{.Text}}{{end}}{{end}}</pre>
+<ol>{{range .Legend}}
+  <li><a href="#{{.ID}}">{{if .Verified}}verified{{else}}unverified{{end}}</a>: {{.Text}}</li>{{end}}
+</ol>
+</section>
+{{end}}</body>
+</html>
+`))
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"unicode/utf8"
 
 	"github.com/smartystreets/smartystreets-go-sdk/us-extract-api"
 	"github.com/smartystreets/smartystreets-go-sdk/wireup"
@@ -24,16 +25,59 @@ func main() {
 		wireup.CustomBaseURL(inputs.baseURL),
 		wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
 		wireup.DebugHTTPOutput(),
-		wireup.WithLicenses(inputs.licenses),
+		wireup.WithLicenses(inputs.Licenses()...),
 	)
 	lookup := inputs.AssembleLookup()
+	documents := inputs.Documents()
 
-	if err := client.SendLookup(lookup); err != nil {
-		log.Fatal(err)
+	if inputs.evaluate {
+		inputs.Evaluate(client, *lookup, documents)
+		return
+	}
+
+	for _, document := range documents {
+		if err := document.Extract(client, *lookup, inputs.maxBytes); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if inputs.redact {
+		redactor := NewRedactor(inputs.mask)
+		redacted := make(map[*Document]string, len(documents))
+		for _, document := range documents {
+			text, err := redactor.Redact(document)
+			if err != nil {
+				log.Fatal(err)
+			}
+			redacted[document] = text
+		}
+		PrintDocuments(documents, func(document *Document) string { return redacted[document] })
+		inputs.WriteRedactionMap(redactor.Mapping())
+		return
+	}
+	if inputs.highlight == highlightHTML {
+		fmt.Print(HighlightHTML(documents))
+		return
+	}
+	if inputs.highlight == highlightTerminal {
+		PrintDocuments(documents, func(document *Document) string {
+			return Highlight(document, inputs.color)
+		})
+		return
+	}
+	if inputs.rewrite {
+		PrintDocuments(documents, func(document *Document) string {
+			return Rewrite(document, inputs.annotation)
+		})
+		return
 	}
 
 	log.Println("Formatted Result:")
-	fmt.Println(helps.DumpJSON(lookup.Result))
+	if len(documents) == 1 && documents[0].Source == "" {
+		fmt.Println(helps.DumpJSON(documents[0].Result))
+	} else {
+		fmt.Println(helps.DumpJSON(TagAddresses(documents)))
+	}
 }
 
 /////////////
@@ -41,7 +85,7 @@ func main() {
 type Inputs struct {
 	*cli.Inputs
 
-	licenses string
+	licenses *cli.ListFlag
 	baseURL  string
 
 	text             string
@@ -49,26 +93,82 @@ type Inputs struct {
 	aggressive       bool
 	lineBreaks       bool
 	addressesPerLine int
+	maxBytes         int
+
+	redact    bool
+	mask      string
+	redactMap string
+
+	rewrite    bool
+	annotation string
+
+	highlight string
+	color     bool
+
+	evaluate       bool
+	evaluations    *cli.ListFlag
+	labelOpen      string
+	labelClose     string
+	minimumOverlap float64
+
+	input      *cli.ListFlag
+	extensions *cli.ListFlag
 
 	lookup *extract.Lookup
 }
 
 func NewInputs() *Inputs {
 	return &Inputs{
-		Inputs: cli.NewInputs(),
+		Inputs:     cli.NewInputs(),
+		licenses:   cli.NewListFlag(',', "us-standard-cloud"),
+		input:      cli.NewListFlag(','),
+		extensions: cli.NewListFlag(',', ".txt", ".html", ".md", ".eml"),
+		evaluations: cli.NewListFlag(';',
+			"aggressive=false",
+			"aggressive=true",
+			"addr_line_breaks=false",
+			"aggressive=true&addr_line_breaks=false",
+			"addr_per_line=1",
+		),
 		lookup: new(extract.Lookup),
 	}
 }
 
 func (this *Inputs) Flags() {
-	flag.StringVar(&this.licenses, "licenses", "us-standard-cloud", "The licenses")
+	flag.Var(this.licenses, "licenses", "The licenses (separated by ',', repeatable)")
 	flag.StringVar(&this.baseURL, "baseURL", os.Getenv("SMARTY_US_EXTRACT_API"), "The URL")
 	flag.StringVar(&this.text, "text", "", "The POST body.")
 	flag.StringVar(&this.html, "html", "", "The html field (derived when blank, 'true' or 'false').")
 	flag.BoolVar(&this.aggressive, "aggressive", false, "The aggressive bool.")
 	flag.BoolVar(&this.lineBreaks, "addr_line_breaks", true, "The addr_line_breaks bool.")
 	flag.IntVar(&this.addressesPerLine, "addr_per_line", 0, "T:he add_per_line field.")
+	flag.IntVar(&this.maxBytes, "max-bytes", 64000, "Text longer than this many bytes is sent in chunks (split between paragraphs or lines) and the results merged.")
+	flag.BoolVar(&this.redact, "redact", false, "Print the text with each address found replaced by the -mask instead of JSON.")
+	flag.StringVar(&this.mask, "mask", "[ADDRESS-%d]", "The -redact placeholder; any '%d' is replaced with the address number.")
+	flag.StringVar(&this.redactMap, "redact-map", "", "With -redact, the CSV file to receive each placeholder along with the address it replaced.")
+	flag.BoolVar(&this.rewrite, "rewrite", false, "Print the text with each verified address replaced by its standardized delivery and last lines instead of JSON.")
+	flag.StringVar(&this.annotation, "annotate", "", "With -rewrite, a note (ie. '[UNVERIFIED]') to place after each address that couldn't be verified (which are otherwise left as is).")
+	flag.StringVar(&this.highlight, "highlight", "", "Print the text with each address numbered and highlighted, followed by a legend of the standardized results, instead of JSON ('"+highlightTerminal+"' or '"+highlightHTML+"').")
+	flag.BoolVar(&this.color, "color", helps.IsTerminal(os.Stdout), "Colorize the -highlight output (defaults to true when writing to a terminal).")
+	flag.BoolVar(&this.evaluate, "evaluate", false, "Measure precision and recall against a corpus of -input documents whose addresses are wrapped in -label-open and -label-close markers.")
+	flag.Var(this.evaluations, "options", "With -evaluate, the query strings of extract options to compare (ie. 'aggressive=true&addr_per_line=1'; separated by ';', repeatable). Options not named keep their flag values.")
+	flag.StringVar(&this.labelOpen, "label-open", "[[", "With -evaluate, the marker preceding each labeled address.")
+	flag.StringVar(&this.labelClose, "label-close", "]]", "With -evaluate, the marker following each labeled address.")
+	flag.Float64Var(&this.minimumOverlap, "overlap", 0.5, "With -evaluate, how much of an extracted address (intersection over union) must overlap a label to count as found.")
+	flag.Var(this.input, "input", "Files or directories (searched recursively) to extract from, each as its own document; '-' reads stdin (separated by ',', repeatable).")
+	flag.Var(this.extensions, "extensions", "The file extensions read from -input directories (separated by ',', repeatable).")
 	this.ParseFlags()
+
+	if this.maxBytes < utf8.UTFMax {
+		log.Fatalf("The -max-bytes value must be at least %d.", utf8.UTFMax)
+	}
+	if this.highlight != "" && this.highlight != highlightTerminal && this.highlight != highlightHTML {
+		log.Fatal("Unrecognized -highlight value:", this.highlight)
+	}
+}
+
+func (this *Inputs) Licenses() []string {
+	return this.licenses.Values
 }
 
 func (this *Inputs) AssembleLookup() *extract.Lookup {
@@ -85,12 +185,72 @@ func (this *Inputs) AssembleLookup() *extract.Lookup {
 
 	this.assembleLookupFromFlags()
 
-	if this.lookup.Text == "" {
+	if this.lookup.Text == "" && len(this.input.Values) == 0 {
 		log.Fatal("No data provided.")
 	}
 
 	return this.lookup
 }
+
+// Documents come from -input when provided, otherwise from the assembled lookup's text.
+func (this *Inputs) Documents() []*Document {
+	if len(this.input.Values) == 0 {
+		return []*Document{{Text: this.lookup.Text}}
+	}
+	documents, err := ReadDocuments(this.input.Values, this.extensions.Values)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Read %d documents.", len(documents))
+	return documents
+}
+
+func (this *Inputs) Evaluate(client *extract.Client, template extract.Lookup, documents []*Document) {
+	labels := make([][]Label, len(documents))
+	for i, document := range documents {
+		var err error
+		if labels[i], err = ParseLabels(document, this.labelOpen, this.labelClose); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var evaluations []*Evaluation
+	for _, options := range this.evaluationOptions() {
+		lookup, err := ApplyOptions(template, options)
+		if err != nil {
+			log.Fatal(err)
+		}
+		evaluation := NewEvaluation(options)
+		for i, document := range documents {
+			if err := document.Extract(client, lookup, this.maxBytes); err != nil {
+				log.Fatal(err)
+			}
+			evaluation.Score(document.Source, labels[i], Spans(document.Text, document.Result), this.minimumOverlap)
+		}
+		evaluations = append(evaluations, evaluation)
+		fmt.Println(evaluation)
+	}
+	fmt.Print(SummarizeEvaluations(evaluations))
+}
+
+// evaluationOptions falls back to the flag values alone when every option set given was blank.
+func (this *Inputs) evaluationOptions() []string {
+	if len(this.evaluations.Values) == 0 {
+		return []string{""}
+	}
+	return this.evaluations.Values
+}
+
+func (this *Inputs) WriteRedactionMap(mapping *helps.Table) {
+	if this.redactMap == "" {
+		return
+	}
+	if err := helps.WriteTableFile(this.redactMap, mapping); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Wrote %d redactions to: %s", len(mapping.Rows), this.redactMap)
+}
+
 func (this *Inputs) assembleLookupFromFlags() {
 	this.lookup.Text = this.text
 	this.lookup.AddressesPerLine = this.addressesPerLine
@@ -99,7 +259,7 @@ func (this *Inputs) assembleLookupFromFlags() {
 	this.lookup.HTML = extract.HTMLPayload(this.html)
 }
 func (this *Inputs) assembleLookupFromQueryString(values url.Values) bool {
-	this.lookup.Text = this.text
+	this.lookup.Text = values.Get("text")
 	this.lookup.AddressesPerLine, _ = strconv.Atoi(values.Get("addr_per_line"))
 	this.lookup.AddressesWithLineBreaks, _ = strconv.ParseBool(values.Get("addr_line_breaks"))
 	this.lookup.Aggressive, _ = strconv.ParseBool(values.Get("aggressive"))
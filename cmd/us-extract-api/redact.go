@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+// Redactor replaces addresses with placeholders, numbered across all documents
+// so that each placeholder in the mapping is unique.
+type Redactor struct {
+	mask    string // any %d in the mask is replaced with the address number
+	count   int
+	mapping *helps.Table
+}
+
+func NewRedactor(mask string) *Redactor {
+	return &Redactor{
+		mask:    mask,
+		mapping: &helps.Table{Header: []string{"placeholder", "source", "line", "start", "end", "verified", "text"}},
+	}
+}
+
+// Redact replaces every address in the document, merging overlapping addresses into a single
+// placeholder. An address that can't be located in the text is an error, as it would otherwise
+// be left in the output as is.
+func (this *Redactor) Redact(document *Document) (string, error) {
+	spans, err := Cover(document.Text, document.Result)
+	if err != nil {
+		if document.Source != "" {
+			err = fmt.Errorf("%s: %s", document.Source, err)
+		}
+		return "", err
+	}
+	runes := []rune(document.Text)
+	return Replace(document.Text, spans, func(_ int, span Span) string {
+		this.count++
+		placeholder := strings.Replace(this.mask, "%d", strconv.Itoa(this.count), -1)
+		this.mapping.Rows = append(this.mapping.Rows, []string{
+			placeholder,
+			document.Source,
+			strconv.Itoa(span.Address.Line),
+			strconv.Itoa(span.Start),
+			strconv.Itoa(span.End),
+			strconv.FormatBool(span.Address.Verified),
+			string(runes[span.Start:span.End]),
+		})
+		return placeholder
+	}), nil
+}
+
+func (this *Redactor) Mapping() *helps.Table {
+	return this.mapping
+}
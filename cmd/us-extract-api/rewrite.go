@@ -0,0 +1,29 @@
+package main
+
+import "strings"
+
+// Rewrite replaces each verified address with its standardized delivery line(s) and last line.
+// Unverified addresses are left untouched, or marked with the annotation when one is given.
+func Rewrite(document *Document, annotation string) string {
+	return Replace(document.Text, Spans(document.Text, document.Result), func(_ int, span Span) string {
+		candidates := span.Address.APIOutput
+		if !span.Address.Verified || len(candidates) == 0 {
+			if annotation == "" {
+				return span.Address.Text
+			}
+			return span.Address.Text + " " + annotation
+		}
+		lines := []string{candidates[0].DeliveryLine1}
+		if candidates[0].DeliveryLine2 != "" {
+			lines = append(lines, candidates[0].DeliveryLine2)
+		}
+		lines = append(lines, candidates[0].LastLine)
+
+		// Keep the shape of the original: one line per line if it spanned several, otherwise a single line.
+		separator := ", "
+		if strings.Contains(span.Address.Text, "\n") {
+			separator = "\n"
+		}
+		return strings.Join(lines, separator)
+	})
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-extract-api"
+)
+
+// Span is a range of characters in a document along with the address found there.
+type Span struct {
+	Start   int
+	End     int
+	Address *extract.ExtractedAddress
+}
+
+// Spans locates each address in the text, in order, skipping any that overlap an earlier one.
+// The start/end offsets are trusted when the text between them is the address; otherwise
+// (ie. if the offsets count bytes rather than characters) the address text is searched for.
+func Spans(text string, result *extract.Result) (spans []Span) {
+	if result == nil {
+		return nil
+	}
+	runes := []rune(text)
+	addresses := append([]*extract.ExtractedAddress{}, result.Addresses...)
+	sort.SliceStable(addresses, func(i, j int) bool { return addresses[i].Start < addresses[j].Start })
+
+	position := 0
+	for _, address := range addresses {
+		start, end, found := locate(text, runes, address, position)
+		if !found {
+			continue
+		}
+		spans = append(spans, Span{Start: start, End: end, Address: address})
+		position = end
+	}
+	return spans
+}
+
+// Cover locates every address in the text, like Spans, except that addresses overlapping an
+// earlier one are merged into its span (so the union of the two is covered) and an address
+// that can't be located at all is an error rather than being skipped.
+func Cover(text string, result *extract.Result) (spans []Span, err error) {
+	if result == nil {
+		return nil, nil
+	}
+	runes := []rune(text)
+	addresses := append([]*extract.ExtractedAddress{}, result.Addresses...)
+	sort.SliceStable(addresses, func(i, j int) bool { return addresses[i].Start < addresses[j].Start })
+
+	position := 0
+	for _, address := range addresses {
+		start, end, found := locate(text, runes, address, position)
+		if !found && len(spans) > 0 {
+			start, end, found = locate(text, runes, address, spans[len(spans)-1].Start)
+		}
+		if !found {
+			return nil, fmt.Errorf("could not locate address %q (offsets %d-%d) in the text", address.Text, address.Start, address.End)
+		}
+		if last := len(spans) - 1; last >= 0 && start < spans[last].End {
+			if end > spans[last].End {
+				spans[last].End = end
+				position = end
+			}
+			continue
+		}
+		spans = append(spans, Span{Start: start, End: end, Address: address})
+		position = end
+	}
+	return spans, nil
+}
+
+func locate(text string, runes []rune, address *extract.ExtractedAddress, from int) (start, end int, found bool) {
+	start, end = address.Start, address.End
+	if start >= from && start <= end && end <= len(runes) && string(runes[start:end]) == address.Text {
+		return start, end, true
+	}
+	if start >= 0 && start <= end && end <= len(text) && text[start:end] == address.Text {
+		if start = len([]rune(text[:start])); start >= from {
+			return start, start + len([]rune(address.Text)), true
+		}
+	}
+	if address.Text == "" {
+		return 0, 0, false
+	}
+	index := strings.Index(string(runes[from:]), address.Text)
+	if index < 0 {
+		return 0, 0, false
+	}
+	start = from + len([]rune(string(runes[from:])[:index]))
+	return start, start + len([]rune(address.Text)), true
+}
+
+// Replace substitutes the text of each span (which must be in order and not overlap).
+func Replace(text string, spans []Span, replacement func(int, Span) string) string {
+	runes := []rune(text)
+	builder := new(strings.Builder)
+	position := 0
+	for i, span := range spans {
+		builder.WriteString(string(runes[position:span.Start]))
+		builder.WriteString(replacement(i, span))
+		position = span.End
+	}
+	builder.WriteString(string(runes[position:]))
+	return builder.String()
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-extract-api"
+)
+
+func extracted(text string, start, end int) *extract.ExtractedAddress {
+	return &extract.ExtractedAddress{Text: text, Start: start, End: end}
+}
+
+func ranges(spans []Span) (values [][2]int) {
+	for _, span := range spans {
+		values = append(values, [2]int{span.Start, span.End})
+	}
+	return values
+}
+
+func TestSpans(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		text      string
+		addresses []*extract.ExtractedAddress
+		expected  string
+	}{
+		{
+			name:      "character offsets",
+			text:      "Go to 1 Main St now",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St", 6, 15)},
+			expected:  "[[6 15]]",
+		},
+		{
+			name:      "adjacent",
+			text:      "1 Main St2 Elm St",
+			addresses: []*extract.ExtractedAddress{extracted("2 Elm St", 9, 17), extracted("1 Main St", 0, 9)},
+			expected:  "[[0 9] [9 17]]",
+		},
+		{
+			name:      "overlapping is skipped",
+			text:      "1 Main St Provo",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St", 0, 9), extracted("Main St Provo", 2, 15)},
+			expected:  "[[0 9]]",
+		},
+		{
+			name:      "multi-byte text with character offsets",
+			text:      "Café: 1 Main St",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St", 6, 15)},
+			expected:  "[[6 15]]",
+		},
+		{
+			name:      "multi-byte text with byte offsets",
+			text:      "Café: 1 Main St",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St", 7, 16)},
+			expected:  "[[6 15]]",
+		},
+		{
+			name:      "the same address twice",
+			text:      "1 Main St, then 1 Main St",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St", 0, 9), extracted("1 Main St", 0, 9)},
+			expected:  "[[0 9] [16 25]]",
+		},
+		{
+			name:      "the same address twice, with byte offsets",
+			text:      "Café 1 Main St, then 1 Main St",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St", 6, 15), extracted("1 Main St", 6, 15)},
+			expected:  "[[5 14] [21 30]]",
+		},
+		{
+			name:      "unlocatable is skipped",
+			text:      "1 Main St",
+			addresses: []*extract.ExtractedAddress{extracted("2 Elm St", 0, 8)},
+			expected:  "[]",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			spans := Spans(test.text, &extract.Result{Addresses: test.addresses})
+			if actual := fmt.Sprint(ranges(spans)); actual != test.expected {
+				t.Errorf("got %s, want %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestCover(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		text      string
+		addresses []*extract.ExtractedAddress
+		expected  string
+	}{
+		{
+			name:      "adjacent",
+			text:      "1 Main St2 Elm St",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St", 0, 9), extracted("2 Elm St", 9, 17)},
+			expected:  "[[0 9] [9 17]]",
+		},
+		{
+			name:      "overlapping is merged",
+			text:      "1 Main St Provo",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St", 0, 9), extracted("Main St Provo", 2, 15)},
+			expected:  "[[0 15]]",
+		},
+		{
+			name:      "contained is merged",
+			text:      "1 Main St Provo",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St Provo", 0, 15), extracted("Main St", 2, 9)},
+			expected:  "[[0 15]]",
+		},
+		{
+			name:      "overlapping with byte offsets in multi-byte text",
+			text:      "Café 1 Main St Provo",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St", 6, 15), extracted("Main St Provo", 8, 21)},
+			expected:  "[[5 20]]",
+		},
+		{
+			name:      "the same address twice",
+			text:      "1 Main St, then 1 Main St",
+			addresses: []*extract.ExtractedAddress{extracted("1 Main St", 0, 9), extracted("1 Main St", 0, 9)},
+			expected:  "[[0 9] [16 25]]",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			spans, err := Cover(test.text, &extract.Result{Addresses: test.addresses})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := fmt.Sprint(ranges(spans)); actual != test.expected {
+				t.Errorf("got %s, want %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestCoverUnlocatable(t *testing.T) {
+	_, err := Cover("1 Main St", &extract.Result{Addresses: []*extract.ExtractedAddress{extracted("2 Elm St", 0, 8)}})
+	if err == nil {
+		t.Error("expected an error for an address missing from the text")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	text := "Café: 1 Main St2 Elm St!"
+	spans := []Span{{Start: 6, End: 15}, {Start: 15, End: 23}}
+	actual := Replace(text, spans, func(i int, span Span) string { return fmt.Sprintf("[%d]", i) })
+	if expected := "Café: [0][1]!"; actual != expected {
+		t.Errorf("got %q, want %q", actual, expected)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	reverse "github.com/smartystreets/smartystreets-go-sdk/us-reverse-geo-api"
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+
+	"github.com/mdwhatcott/smarty-cli"
+)
+
+const (
+	statusMatch      = "match"      // the nearest reverse geo address is the original
+	statusNearby     = "nearby"     // the original is among the reverse geo results, just not the nearest
+	statusMismatch   = "mismatch"   // the original isn't among the reverse geo results
+	statusUnverified = "unverified" // US Street found no candidate
+	statusNoGeocode  = "no_geocode" // the candidate has no coordinates
+	statusNoResults  = "no_results" // reverse geo found nothing near the coordinates
+	statusError      = "error"
+)
+
+// Check is the round trip of one address: verified, then reverse geocoded from its coordinates.
+type Check struct {
+	Lookup   *street.Lookup
+	Results  []reverse.Result
+	Err      string
+	Status   string
+	Rank     int     // the original's position among the reverse geo results (1 is the nearest, 0 when absent)
+	Distance float64 // from the coordinates to the original's reverse geo result, in meters
+}
+
+func (this *Check) candidate() *street.Candidate {
+	if len(this.Lookup.Results) == 0 {
+		return nil
+	}
+	return this.Lookup.Results[0]
+}
+
+// Geocoded reports whether there are coordinates to reverse geocode.
+func (this *Check) Geocoded() bool {
+	candidate := this.candidate()
+	return candidate != nil && candidate.Metadata.Precision != "" && candidate.Metadata.Precision != "Unknown" &&
+		(candidate.Metadata.Latitude != 0 || candidate.Metadata.Longitude != 0)
+}
+
+// Classify compares the reverse geo results with the verified address.
+func (this *Check) Classify() {
+	switch {
+	case this.Err != "":
+		this.Status = statusError
+	case this.candidate() == nil:
+		this.Status = statusUnverified
+	case !this.Geocoded():
+		this.Status = statusNoGeocode
+	case len(this.Results) == 0:
+		this.Status = statusNoResults
+	default:
+		this.Status = statusMismatch
+		original := originalKey(this.candidate())
+		for i, result := range this.Results {
+			if resultKey(result) != original {
+				continue
+			}
+			this.Rank, this.Distance = i+1, result.Distance
+			if i == 0 {
+				this.Status = statusMatch
+			} else {
+				this.Status = statusNearby
+			}
+			break
+		}
+	}
+}
+
+// Flagged reports whether the geocode looks off: anything but a match, or a match farther away than maxDistance (when positive).
+func (this *Check) Flagged(maxDistance float64) bool {
+	if this.Status != statusMatch {
+		return true
+	}
+	return maxDistance > 0 && this.Distance > maxDistance
+}
+
+// originalKey is the primary street address (no secondary) and ZIP Code, as reverse geo reports them.
+func originalKey(candidate *street.Candidate) string {
+	components := candidate.Components
+	return normalize(
+		components.PrimaryNumber,
+		components.StreetPredirection,
+		components.StreetName,
+		components.StreetSuffix,
+		components.StreetPostdirection,
+		components.ZIPCode,
+	)
+}
+
+func resultKey(result reverse.Result) string {
+	return normalize(result.Address.Street, result.Address.ZIPCode)
+}
+
+func normalize(parts ...string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(strings.Join(parts, " ")), " "))
+}
+
+///////////////////
+
+// ReverseGeocode sends a reverse geo lookup for every geocoded check, several at a time
+// (and no more than rate per second, when positive).
+func ReverseGeocode(client *reverse.Client, checks []*Check, concurrency int, rate float64) {
+	var pending []*Check
+	var lookups []*reverse.Lookup
+	for _, check := range checks {
+		if !check.Geocoded() {
+			continue
+		}
+		metadata := check.candidate().Metadata
+		pending = append(pending, check)
+		lookups = append(lookups, &reverse.Lookup{Latitude: metadata.Latitude, Longitude: metadata.Longitude})
+	}
+	for i, err := range cli.SendReverseLookups(client, lookups, concurrency, rate) {
+		if err != nil {
+			pending[i].Err = err.Error()
+			continue
+		}
+		pending[i].Results = lookups[i].Response.Results
+	}
+}
+
+///////////////////
+
+var checkColumns = []string{
+	"delivery_line_1", "last_line", "latitude", "longitude", "precision",
+	"nearest_street", "nearest_city", "nearest_state_abbreviation", "nearest_zipcode", "nearest_distance",
+	"status", "original_rank", "original_distance", "flagged", "error",
+}
+
+// Columns describes the check (aligned with checkColumns).
+func (this *Check) Columns(maxDistance float64) []string {
+	columns := make([]string, len(checkColumns))
+	if candidate := this.candidate(); candidate != nil {
+		columns[0] = candidate.DeliveryLine1
+		columns[1] = candidate.LastLine
+		columns[4] = candidate.Metadata.Precision
+		if this.Geocoded() {
+			columns[2] = strconv.FormatFloat(candidate.Metadata.Latitude, 'f', -1, 64)
+			columns[3] = strconv.FormatFloat(candidate.Metadata.Longitude, 'f', -1, 64)
+		}
+	}
+	if len(this.Results) > 0 {
+		nearest := this.Results[0]
+		columns[5] = nearest.Address.Street
+		columns[6] = nearest.Address.City
+		columns[7] = nearest.Address.StateAbbreviation
+		columns[8] = nearest.Address.ZIPCode
+		columns[9] = strconv.FormatFloat(nearest.Distance, 'f', -1, 64)
+	}
+	columns[10] = this.Status
+	if this.Rank > 0 {
+		columns[11] = strconv.Itoa(this.Rank)
+		columns[12] = strconv.FormatFloat(this.Distance, 'f', -1, 64)
+	}
+	columns[13] = strconv.FormatBool(this.Flagged(maxDistance))
+	columns[14] = this.Err
+	return columns
+}
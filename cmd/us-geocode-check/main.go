@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+	"github.com/smartystreets/smartystreets-go-sdk/wireup"
+
+	"github.com/mdwhatcott/smarty-cli"
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+func main() {
+	log.SetFlags(log.Lmicroseconds)
+
+	inputs := NewInputs()
+	streets := wireup.BuildUSStreetAPIClient(
+		wireup.CustomBaseURL(inputs.streetURL),
+		wireup.WithLicenses(inputs.streetLicenses.Values...),
+		wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
+	)
+	reverseGeo := wireup.BuildUSReverseGeocodingAPIClient(
+		wireup.CustomBaseURL(inputs.reverseURL),
+		wireup.WithLicenses(inputs.reverseLicenses.Values...),
+		wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
+	)
+	table := inputs.ReadTable()
+
+	var lookups []*street.Lookup
+	var checks []*Check
+	for _, row := range table.Rows {
+		lookup := cli.NewStreetLookup(table.Values(row))
+		lookup.MaxCandidates = 1
+		lookups = append(lookups, lookup)
+		checks = append(checks, &Check{Lookup: lookup})
+	}
+	if err := cli.SendStreetLookups(streets, lookups); err != nil {
+		log.Fatal(err)
+	}
+	ReverseGeocode(reverseGeo, checks, inputs.concurrency, inputs.rate)
+
+	results := &helps.Table{Header: append(append([]string{}, table.Header...), checkColumns...)}
+	statuses := make(map[string]int)
+	flagged := 0
+	for i, check := range checks {
+		check.Classify()
+		statuses[check.Status]++
+		if check.Flagged(inputs.maxDistance) {
+			flagged++
+		}
+		row := make([]string, len(table.Header))
+		copy(row, table.Rows[i])
+		results.Rows = append(results.Rows, append(row, check.Columns(inputs.maxDistance)...))
+	}
+	log.Printf("Flagged %d of %d addresses (%s).", flagged, len(checks), helps.SummarizeCounts(statuses))
+	inputs.WriteTable(results)
+}
+
+///////////////////
+
+type Inputs struct {
+	*cli.Inputs
+
+	streetURL       string
+	reverseURL      string
+	streetLicenses  *cli.ListFlag
+	reverseLicenses *cli.ListFlag
+
+	address     string
+	input       string
+	output      string
+	maxDistance float64
+	concurrency int
+	rate        float64
+}
+
+func NewInputs() *Inputs {
+	this := &Inputs{
+		Inputs:          cli.NewInputs(),
+		streetLicenses:  cli.NewListFlag(',', "us-core-cloud"),
+		reverseLicenses: cli.NewListFlag(',', "us-reverse-geocoding-cloud"),
+	}
+	this.flags()
+	return this
+}
+
+func (this *Inputs) flags() {
+	flag.StringVar(&this.streetURL, "streetURL", os.Getenv("SMARTY_US_STREET_API"), "The US Street API URL")
+	flag.StringVar(&this.reverseURL, "reverseURL", os.Getenv("SMARTY_US_REVERSE_GEO_API"), "The US Reverse Geocoding API URL")
+	flag.Var(this.streetLicenses, "street-licenses", "The US Street API licenses (separated by ',', repeatable)")
+	flag.Var(this.reverseLicenses, "reverse-licenses", "The US Reverse Geocoding API licenses (separated by ',', repeatable)")
+	flag.StringVar(&this.address, "address", "", "A single freeform address to check (instead of -input).")
+	flag.StringVar(&this.input, "input", "-", "The CSV file of addresses (with a header row naming the US Street API fields: street, city, state, zipcode, etc...). Defaults to stdin.")
+	flag.StringVar(&this.output, "output", "-", "Where to write the addresses joined to their round trip results ('-' for stdout).")
+	flag.Float64Var(&this.maxDistance, "max-distance", 0, "Also flag matches whose reverse geo result is farther than this many meters from the geocode (0 to ignore distance).")
+	flag.IntVar(&this.concurrency, "concurrency", 8, "How many reverse geo lookups to send at once.")
+	flag.Float64Var(&this.rate, "rate", 0, "The most reverse geo lookups to send per second (0 for no limit).")
+	this.ParseFlags()
+}
+
+// ReadTable reads the -input CSV, or makes a one-row table of the -address.
+func (this *Inputs) ReadTable() *helps.Table {
+	if this.address != "" {
+		return &helps.Table{Header: []string{"address"}, Rows: [][]string{{this.address}}}
+	}
+	table, err := helps.ReadTableFile(this.input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return table
+}
+
+func (this *Inputs) WriteTable(table *helps.Table) {
+	if err := helps.WriteTableFile(this.output, table); err != nil {
+		log.Fatal(err)
+	}
+}
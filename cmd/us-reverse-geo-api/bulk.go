@@ -0,0 +1,342 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	reverse "github.com/smartystreets/smartystreets-go-sdk/us-reverse-geo-api"
+
+	"github.com/mdwhatcott/smarty-cli"
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+const (
+	sourceCSV     = "csv"
+	sourceGeoJSON = "geojson"
+	sourceGPX     = "gpx"
+)
+
+// Record is one point to reverse geocode along with whatever it came from.
+type Record struct {
+	Latitude  float64
+	Longitude float64
+	Row       []string               // the source columns, aligned with Source.Header
+	Feature   map[string]interface{} // the original feature (GeoJSON sources only)
+	Results   []reverse.Result
+	Err       string // why the point couldn't be reverse geocoded
+}
+
+// Source is a collection of points in one of the supported formats.
+type Source struct {
+	Format  string
+	Header  []string
+	Records []*Record
+	raw     map[string]interface{} // the original feature collection (GeoJSON sources only)
+}
+
+// SourceFormat is the format named, or else the one implied by the file extension (CSV by default).
+func SourceFormat(format, path string) string {
+	if format != "" {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".geojson", ".json":
+		return sourceGeoJSON
+	case ".gpx":
+		return sourceGPX
+	default:
+		return sourceCSV
+	}
+}
+
+func ReadSource(reader io.Reader, format string, columns CoordinateColumns) (*Source, error) {
+	switch format {
+	case sourceCSV:
+		return readCSV(reader, columns)
+	case sourceGeoJSON:
+		return readGeoJSON(reader)
+	case sourceGPX:
+		return readGPX(reader)
+	default:
+		return nil, fmt.Errorf("unrecognized input format: %s", format)
+	}
+}
+
+///////////////////
+
+// CoordinateColumns names the CSV columns holding the coordinates.
+// When blank, a few common names are tried.
+type CoordinateColumns struct {
+	Latitude  string
+	Longitude string
+}
+
+var (
+	latitudeColumns  = []string{"latitude", "lat", "y"}
+	longitudeColumns = []string{"longitude", "lon", "lng", "long", "x"}
+	pointColumns     = []string{"point", "coordinates", "location", "latlng"}
+)
+
+func readCSV(reader io.Reader, columns CoordinateColumns) (*Source, error) {
+	table, err := helps.ReadTable(reader)
+	if err != nil {
+		return nil, err
+	}
+	latitude, err := findColumn(table, columns.Latitude, latitudeColumns)
+	if err != nil && columns.Latitude == "" && columns.Longitude == "" {
+		if point, found := findColumn(table, "", pointColumns); found == nil {
+			return readCSVPoints(table, point), nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	longitude, err := findColumn(table, columns.Longitude, longitudeColumns)
+	if err != nil {
+		return nil, err
+	}
+	source := &Source{Format: sourceCSV, Header: table.Header}
+	for _, row := range table.Rows {
+		record := &Record{Row: row}
+		record.parse(table.Get(row, latitude), table.Get(row, longitude))
+		source.Records = append(source.Records, record)
+	}
+	return source, nil
+}
+
+// readCSVPoints reads both coordinates from a single column (ie. "40.25,-111.67" or "40°15'N 111°40'W").
+func readCSVPoints(table *helps.Table, column string) *Source {
+	source := &Source{Format: sourceCSV, Header: table.Header}
+	for _, row := range table.Rows {
+		record := &Record{Row: row}
+		record.parsePoint(table.Get(row, column))
+		source.Records = append(source.Records, record)
+	}
+	return source
+}
+
+func findColumn(table *helps.Table, name string, defaults []string) (string, error) {
+	if name != "" {
+		if table.Column(name) < 0 {
+			return "", fmt.Errorf("the input has no column named: %s", name)
+		}
+		return name, nil
+	}
+	for _, candidate := range defaults {
+		if table.Column(candidate) >= 0 {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("the input has none of these columns: %s", strings.Join(defaults, ", "))
+}
+
+func (this *Record) parse(latitude, longitude string) {
+	var err error
+	if this.Latitude, err = helps.ParseLatitude(latitude); err != nil {
+		this.Err = err.Error()
+	} else if this.Longitude, err = helps.ParseLongitude(longitude); err != nil {
+		this.Err = err.Error()
+	}
+}
+
+func (this *Record) parsePoint(point string) {
+	if coordinate, err := helps.ParseCoordinate(point); err != nil {
+		this.Err = err.Error()
+	} else {
+		this.Latitude, this.Longitude = coordinate.Latitude, coordinate.Longitude
+	}
+}
+
+///////////////////
+
+func readGeoJSON(reader io.Reader) (*Source, error) {
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var collection map[string]interface{}
+	if err := json.Unmarshal(content, &collection); err != nil {
+		return nil, err
+	}
+	if collection["type"] != "FeatureCollection" {
+		return nil, fmt.Errorf("expected a GeoJSON FeatureCollection, got: %v", collection["type"])
+	}
+	features, _ := collection["features"].([]interface{})
+	source := &Source{Format: sourceGeoJSON, raw: collection}
+	for i, item := range features {
+		feature, _ := item.(map[string]interface{})
+		record := &Record{Feature: feature}
+		geometry, _ := feature["geometry"].(map[string]interface{})
+		coordinates, _ := geometry["coordinates"].([]interface{})
+		if geometry["type"] != "Point" || len(coordinates) < 2 {
+			record.Err = fmt.Sprintf("feature %d is not a Point", i)
+		} else {
+			longitude, okLon := coordinates[0].(float64)
+			latitude, okLat := coordinates[1].(float64)
+			record.Latitude, record.Longitude = latitude, longitude
+			if !okLon || !okLat {
+				record.Err = fmt.Sprintf("feature %d has non-numeric coordinates", i)
+			}
+		}
+		source.Records = append(source.Records, record)
+	}
+	return source, nil
+}
+
+///////////////////
+
+type gpxFile struct {
+	Waypoints []gpxPoint `xml:"wpt"`
+	Routes    []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Latitude  string `xml:"lat,attr"`
+	Longitude string `xml:"lon,attr"`
+	Name      string `xml:"name"`
+	Time      string `xml:"time"`
+}
+
+func readGPX(reader io.Reader) (*Source, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(reader).Decode(&file); err != nil {
+		return nil, err
+	}
+	source := &Source{Format: sourceGPX, Header: []string{"type", "name", "time", "latitude", "longitude"}}
+	add := func(kind string, point gpxPoint) {
+		record := &Record{Row: []string{kind, point.Name, point.Time, point.Latitude, point.Longitude}}
+		record.parse(point.Latitude, point.Longitude)
+		source.Records = append(source.Records, record)
+	}
+	for _, point := range file.Waypoints {
+		add("wpt", point)
+	}
+	for _, route := range file.Routes {
+		for _, point := range route.Points {
+			add("rtept", point)
+		}
+	}
+	for _, track := range file.Tracks {
+		for _, segment := range track.Segments {
+			for _, point := range segment.Points {
+				add("trkpt", point)
+			}
+		}
+	}
+	return source, nil
+}
+
+///////////////////
+
+// ReverseGeocode sends one lookup per valid record, several at a time (and no more than rate per second, when positive).
+// A failed lookup is noted on its record rather than ending the run.
+func ReverseGeocode(client *reverse.Client, records []*Record, concurrency int, rate float64) {
+	var pending []*Record
+	var lookups []*reverse.Lookup
+	for _, record := range records {
+		if record.Err != "" {
+			continue
+		}
+		pending = append(pending, record)
+		lookups = append(lookups, &reverse.Lookup{Latitude: record.Latitude, Longitude: record.Longitude})
+	}
+	for i, err := range cli.SendReverseLookups(client, lookups, concurrency, rate) {
+		if err != nil {
+			pending[i].Err = err.Error()
+			continue
+		}
+		pending[i].Results = lookups[i].Response.Results
+	}
+}
+
+///////////////////
+
+var resultColumns = []string{
+	"results", "street", "city", "state_abbreviation", "zipcode",
+	"distance", "distance_feet", "distance_miles", "bearing", "compass_point", "accuracy", "error",
+}
+
+// Write joins each record to its nearest result: as added columns for CSV and GPX sources,
+// or as added properties (with every result under "reverse_geo") for GeoJSON sources.
+func (this *Source) Write(writer io.Writer) error {
+	if this.Format == sourceGeoJSON {
+		return this.writeGeoJSON(writer)
+	}
+	table := &helps.Table{Header: append(append([]string{}, this.Header...), resultColumns...)}
+	for _, record := range this.Records {
+		row := make([]string, len(this.Header))
+		copy(row, record.Row)
+		table.Rows = append(table.Rows, append(row, record.nearest()...))
+	}
+	return table.Write(writer)
+}
+
+func (this *Record) coordinate() helps.Coordinate {
+	return helps.Coordinate{Latitude: this.Latitude, Longitude: this.Longitude}
+}
+
+func (this *Record) nearest() []string {
+	if len(this.Results) == 0 {
+		return []string{"0", "", "", "", "", "", "", "", "", "", "", this.Err}
+	}
+	nearest := Enrich(this.coordinate(), this.Results[0])
+	return []string{
+		strconv.Itoa(len(this.Results)),
+		nearest.Address.Street,
+		nearest.Address.City,
+		nearest.Address.StateAbbreviation,
+		nearest.Address.ZIPCode,
+		formatFloat(nearest.Distance),
+		formatFloat(nearest.DistanceFeet),
+		formatFloat(nearest.DistanceMiles),
+		formatFloat(nearest.Bearing),
+		nearest.CompassPoint,
+		nearest.Coordinate.Accuracy,
+		this.Err,
+	}
+}
+
+func (this *Source) writeGeoJSON(writer io.Writer) error {
+	for _, record := range this.Records {
+		if record.Feature == nil {
+			continue
+		}
+		properties, _ := record.Feature["properties"].(map[string]interface{})
+		if properties == nil {
+			properties = make(map[string]interface{})
+			record.Feature["properties"] = properties
+		}
+		if record.Err != "" {
+			properties["reverse_geo_error"] = record.Err
+		}
+		enriched := EnrichAll(record.coordinate(), record.Results)
+		if len(enriched) > 0 {
+			nearest := enriched[0]
+			properties["reverse_geo_street"] = nearest.Address.Street
+			properties["reverse_geo_city"] = nearest.Address.City
+			properties["reverse_geo_state_abbreviation"] = nearest.Address.StateAbbreviation
+			properties["reverse_geo_zipcode"] = nearest.Address.ZIPCode
+			properties["reverse_geo_distance"] = nearest.Distance
+			properties["reverse_geo_distance_feet"] = nearest.DistanceFeet
+			properties["reverse_geo_distance_miles"] = nearest.DistanceMiles
+			properties["reverse_geo_bearing"] = nearest.Bearing
+			properties["reverse_geo_compass_point"] = nearest.CompassPoint
+		}
+		properties["reverse_geo"] = enriched
+	}
+	_, err := fmt.Fprintln(writer, helps.DumpJSON(this.raw))
+	return err
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	reverse "github.com/smartystreets/smartystreets-go-sdk/us-reverse-geo-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+// EnrichedResult adds the bearing (from the query point) and friendlier distance units to a result.
+// The API reports distance in meters.
+type EnrichedResult struct {
+	reverse.Result
+	Bearing       float64 `json:"bearing"`
+	CompassPoint  string  `json:"compass_point"`
+	DistanceFeet  float64 `json:"distance_feet"`
+	DistanceMiles float64 `json:"distance_miles"`
+}
+
+func Enrich(from helps.Coordinate, result reverse.Result) EnrichedResult {
+	to := helps.Coordinate{Latitude: result.Coordinate.Latitude, Longitude: result.Coordinate.Longitude}
+	bearing := math.Mod(round(helps.Bearing(from, to), 1), 360) // ie. 359.96 rounds to 360.0, which is 0
+	return EnrichedResult{
+		Result:        result,
+		Bearing:       bearing,
+		CompassPoint:  helps.CompassPoint(bearing),
+		DistanceFeet:  round(result.Distance*helps.FeetPerMeter, 1),
+		DistanceMiles: round(result.Distance/helps.MetersPerMile, 3),
+	}
+}
+
+func EnrichAll(from helps.Coordinate, results []reverse.Result) (enriched []EnrichedResult) {
+	for _, result := range results {
+		enriched = append(enriched, Enrich(from, result))
+	}
+	return enriched
+}
+
+func round(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
+
+///////////////////
+
+// ResultFilter keeps the nearest results (when Nearest is positive) within MaxDistance meters (when positive).
+type ResultFilter struct {
+	Nearest     int
+	MaxDistance float64
+}
+
+func (this ResultFilter) Apply(results []reverse.Result) []reverse.Result {
+	if this.Nearest <= 0 && this.MaxDistance <= 0 {
+		return results
+	}
+	sorted := append([]reverse.Result{}, results...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Distance < sorted[j].Distance
+	})
+	kept := sorted[:0]
+	for _, result := range sorted {
+		if this.MaxDistance > 0 && result.Distance > this.MaxDistance {
+			break
+		}
+		if this.Nearest > 0 && len(kept) == this.Nearest {
+			break
+		}
+		kept = append(kept, result)
+	}
+	return kept
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
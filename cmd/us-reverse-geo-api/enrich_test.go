@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	reverse "github.com/smartystreets/smartystreets-go-sdk/us-reverse-geo-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+func TestEnrich(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		to           reverse.Coordinate
+		bearing      float64
+		compassPoint string
+	}{
+		{name: "east", to: reverse.Coordinate{Latitude: 0, Longitude: 0.001}, bearing: 90, compassPoint: "E"},
+		{name: "rounds up to north", to: reverse.Coordinate{Latitude: 1, Longitude: -0.0001}, bearing: 0, compassPoint: "N"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			enriched := Enrich(helps.Coordinate{}, reverse.Result{Coordinate: test.to, Distance: 1609.344})
+			if enriched.Bearing != test.bearing || enriched.CompassPoint != test.compassPoint {
+				t.Errorf("got %v %s, want %v %s", enriched.Bearing, enriched.CompassPoint, test.bearing, test.compassPoint)
+			}
+			if enriched.Distance != 1609.344 || enriched.DistanceFeet != 5280 || enriched.DistanceMiles != 1 {
+				t.Errorf("got %v m, %v ft, %v mi", enriched.Distance, enriched.DistanceFeet, enriched.DistanceMiles)
+			}
+		})
+	}
+}
+
+func TestResultFilterApply(t *testing.T) {
+	results := []reverse.Result{{Distance: 30}, {Distance: 10}, {Distance: 50}, {Distance: 20}}
+	for _, test := range []struct {
+		name     string
+		filter   ResultFilter
+		expected []float64
+	}{
+		{name: "unfiltered", filter: ResultFilter{}, expected: []float64{30, 10, 50, 20}},
+		{name: "nearest", filter: ResultFilter{Nearest: 2}, expected: []float64{10, 20}},
+		{name: "more nearest than results", filter: ResultFilter{Nearest: 10}, expected: []float64{10, 20, 30, 50}},
+		{name: "max distance", filter: ResultFilter{MaxDistance: 30}, expected: []float64{10, 20, 30}},
+		{name: "max distance below all", filter: ResultFilter{MaxDistance: 5}, expected: nil},
+		{name: "both", filter: ResultFilter{Nearest: 2, MaxDistance: 15}, expected: []float64{10}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var actual []float64
+			for _, result := range test.filter.Apply(results) {
+				actual = append(actual, result.Distance)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+	if results[0].Distance != 30 {
+		t.Error("the input results should be left in their original order")
+	}
+}
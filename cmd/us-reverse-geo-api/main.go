@@ -3,10 +3,10 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/url"
 	"os"
-	"strconv"
 	"strings"
 
 	reverse "github.com/smartystreets/smartystreets-go-sdk/us-reverse-geo-api"
@@ -20,20 +20,47 @@ func main() {
 	log.SetFlags(log.Lmicroseconds)
 
 	inputs := NewInputs()
-	client := wireup.BuildUSReverseGeocodingAPIClient(
+	options := []wireup.Option{
 		wireup.CustomBaseURL(inputs.baseURL),
 		wireup.WithLicenses(inputs.Licenses()...),
 		wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
-		wireup.DebugHTTPOutput(),
-	)
+	}
+	if !inputs.Bulk() {
+		options = append(options, wireup.DebugHTTPOutput()) // a dump of every request per row, photo or sample point would bury the results
+	}
+	client := wireup.BuildUSReverseGeocodingAPIClient(options...)
+
+	if inputs.Sampling() {
+		records := SampleRecords(inputs.Grid())
+		ReverseGeocode(client, records, inputs.concurrency, inputs.rate)
+		inputs.FilterRecords(records)
+		inputs.WriteAddresses(UniqueAddresses(records))
+		return
+	}
+
+	if inputs.Bulk() {
+		source := inputs.ReadSource()
+		ReverseGeocode(client, source.Records, inputs.concurrency, inputs.rate)
+		inputs.FilterRecords(source.Records)
+		inputs.WriteSource(source)
+		return
+	}
+
 	lookup := inputs.PopulateLookup()
 
 	if err := client.SendLookup(lookup); err != nil {
 		log.Fatal(err)
 	}
 
+	from := helps.Coordinate{Latitude: lookup.Latitude, Longitude: lookup.Longitude}
+	results := inputs.filter.Apply(lookup.Response.Results)
+
 	log.Println("Formatted Result:")
-	fmt.Println(helps.DumpJSON(lookup.Response.Results))
+	if dump, found := helps.PointFormats[inputs.format]; found {
+		fmt.Println(dump(Points(from, results)))
+	} else {
+		fmt.Println(helps.DumpJSON(EnrichAll(from, results)))
+	}
 }
 
 ///////////////////
@@ -42,18 +69,38 @@ type Inputs struct {
 	*cli.Inputs
 
 	baseURL  string
-	licenses string
+	licenses *cli.ListFlag
+
+	latitude  string
+	longitude string
+	point     string
 
-	latitude  float64
-	longitude float64
+	format string
+	filter ResultFilter
+
+	input           string
+	inputFormat     string
+	output          string
+	latitudeColumn  string
+	longitudeColumn string
+	concurrency     int
+	photos          *cli.ListFlag
+	rate            float64
+
+	bbox       string
+	polygon    string
+	spacing    float64
+	maxLookups int
 
 	lookup *reverse.Lookup
 }
 
 func NewInputs() *Inputs {
 	this := &Inputs{
-		Inputs: cli.NewInputs(),
-		lookup: new(reverse.Lookup),
+		Inputs:   cli.NewInputs(),
+		licenses: cli.NewListFlag(',', "us-reverse-geocoding-cloud"),
+		photos:   cli.NewListFlag(','),
+		lookup:   new(reverse.Lookup),
 	}
 	this.flags()
 	return this
@@ -61,50 +108,199 @@ func NewInputs() *Inputs {
 
 func (this *Inputs) flags() {
 	flag.StringVar(&this.baseURL, "baseURL", os.Getenv("SMARTY_US_REVERSE_GEO_API"), "The URL")
-	flag.StringVar(&this.licenses, "licenses", "us-reverse-geocoding-cloud", "The licenses")
-	flag.Float64Var(&this.latitude, "latitude", 40.25, "The latitude")
-	flag.Float64Var(&this.longitude, "longitude", -111.67, "The longitude")
+	flag.Var(this.licenses, "licenses", "The licenses (separated by ',', repeatable)")
+	flag.StringVar(&this.latitude, "latitude", "40.25", "The latitude (decimal or degrees/minutes/seconds, ie. 40°15'N).")
+	flag.StringVar(&this.longitude, "longitude", "-111.67", "The longitude (decimal or degrees/minutes/seconds, ie. 111°40'12\"W).")
+	flag.StringVar(&this.point, "point", "", "Both coordinates at once, overriding -latitude and -longitude (ie. '40.25,-111.67', '40°15'N 111°40'W', 'geo:40.25,-111.67' or a geohash).")
+	flag.StringVar(&this.input, "input", "", "A file of points to reverse geocode in bulk ('-' for stdin): CSV with latitude/longitude columns, a GeoJSON FeatureCollection of Points, or GPX waypoints, routes and tracks.")
+	flag.StringVar(&this.inputFormat, "input-format", "", "The -input format ('"+sourceCSV+"', '"+sourceGeoJSON+"' or '"+sourceGPX+"'). Derived from the file extension when blank.")
+	flag.StringVar(&this.output, "output", "-", "Where to write the -input points joined to their results ('-' for stdout). CSV, or GeoJSON for GeoJSON input.")
+	flag.StringVar(&this.latitudeColumn, "latitude-column", "", "The CSV -input column holding latitudes (ie. latitude, lat or y when blank).")
+	flag.StringVar(&this.longitudeColumn, "longitude-column", "", "The CSV -input column holding longitudes (ie. longitude, lon, lng, long or x when blank).")
+	flag.Var(this.photos, "photos", "Geotagged photos (JPEG or TIFF), or directories of them, to reverse geocode by their EXIF GPS coordinates (separated by ',', repeatable). Writes each file name, capture time, coordinates and nearest address to -output.")
+	flag.IntVar(&this.concurrency, "concurrency", 8, "How many -input lookups to send at once.")
+	flag.Float64Var(&this.rate, "rate", 0, "The most lookups to send per second in bulk (0 for no limit).")
+	flag.StringVar(&this.bbox, "bbox", "", "Sample a bounding box ('south,west,north,east') with a grid of lookups, writing the unique addresses found to -output.")
+	flag.StringVar(&this.polygon, "polygon", "", "Sample the Polygon/MultiPolygon geometries of a GeoJSON file (like -bbox).")
+	flag.Float64Var(&this.spacing, "spacing", 250, "The distance between -bbox/-polygon grid points, in meters.")
+	flag.IntVar(&this.maxLookups, "max-lookups", 1000, "The most -bbox/-polygon grid points to send (a budget cap; 0 for no limit).")
+	flag.IntVar(&this.filter.Nearest, "nearest", 0, "Keep only the nearest N results (0 for all).")
+	flag.Float64Var(&this.filter.MaxDistance, "max-distance", 0, "Keep only results within this many meters of the point (0 for any distance).")
+	flag.StringVar(&this.format, "format", helps.FormatJSON, "The output format (choose from: "+helps.PointFormatNames()+").")
 	this.ParseFlags()
+
+	if !helps.IsPointFormat(this.format) {
+		log.Fatal("Unrecognized -format value:", this.format)
+	}
 }
 
 func (this *Inputs) Licenses() []string {
-	return strings.Split(this.licenses, ",")
+	return this.licenses.Values
 }
 
-func (this *Inputs) PopulateLookup() *reverse.Lookup {
-	values, _ := url.ParseQuery(this.RawQuery)
-	this.assembleLookupFromQueryString(values)
+// FilterRecords applies -nearest and -max-distance to each record's results.
+func (this *Inputs) FilterRecords(records []*Record) {
+	for _, record := range records {
+		record.Results = this.filter.Apply(record.Results)
+	}
+}
 
-	if this.lookup.Latitude != 0 && this.lookup.Longitude != 0 {
-		return this.lookup
+// Bulk reports whether the points come from -input, -photos or a sampled area (rather than a single lookup).
+func (this *Inputs) Bulk() bool {
+	return this.input != "" || len(this.photos.Values) > 0 || this.Sampling()
+}
+
+func (this *Inputs) Sampling() bool {
+	return this.bbox != "" || this.polygon != ""
+}
+
+// Grid lays out the sample points, refusing to exceed the -max-lookups budget.
+func (this *Inputs) Grid() []helps.Coordinate {
+	area := this.area()
+	points, err := helps.Grid(area, this.spacing, this.maxLookups)
+	if err == helps.ErrTooManyPoints && this.maxLookups <= 0 {
+		log.Fatalf("The grid has too many points to consider; increase -spacing (currently %vm).", this.spacing)
+	}
+	if err == helps.ErrTooManyPoints {
+		log.Fatalf("The grid needs more than %d lookups (-max-lookups); increase -spacing (currently %vm) or -max-lookups.", this.maxLookups, this.spacing)
+	}
+	if err != nil {
+		log.Fatal(err)
 	}
+	log.Printf("Sampling %d grid points spaced %vm apart.", len(points), this.spacing)
+	return points
+}
 
-	if address, _ := url.Parse(this.RawURL); address != nil {
-		this.assembleLookupFromQueryString(address.Query())
+func (this *Inputs) area() *helps.Area {
+	if this.polygon == "" {
+		box, err := helps.ParseBoundingBox(this.bbox)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return helps.NewBoxArea(box)
 	}
-	if this.lookup.Latitude != 0 && this.lookup.Longitude != 0 {
-		return this.lookup
+	reader, err := helps.OpenInput(this.polygon)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer reader.Close()
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	area, err := helps.ReadGeoJSONArea(content)
+	if err != nil {
+		log.Fatal(err)
 	}
+	return area
+}
 
-	this.assembleLookupFromFlags()
+func (this *Inputs) WriteAddresses(addresses []*FoundAddress) {
+	writer, err := helps.CreateOutput(this.output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer writer.Close()
+
+	log.Printf("Found %d unique addresses.", len(addresses))
+	if err := WriteAddresses(writer, addresses); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (this *Inputs) ReadSource() *Source {
+	if len(this.photos.Values) > 0 {
+		return this.readPhotos()
+	}
+
+	reader, err := helps.OpenInput(this.input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer reader.Close()
+
+	source, err := ReadSource(reader, SourceFormat(this.inputFormat, this.input), CoordinateColumns{
+		Latitude:  this.latitudeColumn,
+		Longitude: this.longitudeColumn,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Read %d points from: %s", len(source.Records), this.input)
+	return source
+}
+
+func (this *Inputs) readPhotos() *Source {
+	source, err := ReadPhotos(this.photos.Values)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Read %d photos from: %s", len(source.Records), strings.Join(this.photos.Values, ", "))
+	return source
+}
+
+func (this *Inputs) WriteSource(source *Source) {
+	writer, err := helps.CreateOutput(this.output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer writer.Close()
+
+	if err := source.Write(writer); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (this *Inputs) PopulateLookup() *reverse.Lookup {
+	values, _ := url.ParseQuery(this.RawQuery)
+	if this.assembleLookupFromQueryString(values) {
+		return this.lookup
+	}
 
-	if this.lookup.Latitude == 0 && this.lookup.Longitude == 0 {
-		log.Fatal("No street provided.")
+	if address, _ := url.Parse(this.RawURL); address != nil && this.assembleLookupFromQueryString(address.Query()) {
+		return this.lookup
 	}
 
+	this.assembleLookupFromFlags()
 	return this.lookup
 }
-func (this *Inputs) assembleLookupFromQueryString(values url.Values) {
-	this.lookup.Latitude = ParseFloat64(values.Get("latitude"))
-	this.lookup.Longitude = ParseFloat64(values.Get("longitude"))
+
+// assembleLookupFromQueryString reports whether the values held a point (or a latitude/longitude pair).
+func (this *Inputs) assembleLookupFromQueryString(values url.Values) bool {
+	if point := values.Get("point"); point != "" {
+		this.assembleLookupFromPoint(point)
+		return true
+	}
+	if values.Get("latitude") == "" && values.Get("longitude") == "" {
+		return false
+	}
+	this.assembleLookupFromPair(values.Get("latitude"), values.Get("longitude"))
+	return true
 }
 
 func (this *Inputs) assembleLookupFromFlags() {
-	this.lookup.Latitude = this.latitude
-	this.lookup.Longitude = this.longitude
+	if this.point != "" {
+		this.assembleLookupFromPoint(this.point)
+	} else {
+		this.assembleLookupFromPair(this.latitude, this.longitude)
+	}
+}
+
+func (this *Inputs) assembleLookupFromPoint(point string) {
+	coordinate, err := helps.ParseCoordinate(point)
+	if err != nil {
+		log.Fatal(err)
+	}
+	this.lookup.Latitude = coordinate.Latitude
+	this.lookup.Longitude = coordinate.Longitude
 }
 
-func ParseFloat64(raw string) float64 {
-	parsed, _ := strconv.ParseFloat(raw, 64)
-	return parsed
+func (this *Inputs) assembleLookupFromPair(latitude, longitude string) {
+	var err error
+	if this.lookup.Latitude, err = helps.ParseLatitude(latitude); err != nil {
+		log.Fatal(err)
+	}
+	if this.lookup.Longitude, err = helps.ParseLongitude(longitude); err != nil {
+		log.Fatal(err)
+	}
 }
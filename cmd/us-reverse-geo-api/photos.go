@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+var photoExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".jpe": true, ".tif": true, ".tiff": true, ".dng": true}
+
+// ReadPhotos gathers the photos named (searching directories recursively) as CSV records
+// of their file name, capture time and EXIF GPS coordinates. Photos without coordinates
+// are kept (with an error) so that they're accounted for in the output.
+func ReadPhotos(paths []string) (*Source, error) {
+	source := &Source{Format: sourceCSV, Header: []string{"file", "captured", "latitude", "longitude"}}
+	for _, path := range paths {
+		err := filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || (name != path && !photoExtensions[strings.ToLower(filepath.Ext(name))]) {
+				return nil
+			}
+			source.Records = append(source.Records, readPhoto(name))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return source, nil
+}
+
+func readPhoto(name string) *Record {
+	record := &Record{Row: []string{name, "", "", ""}}
+	file, err := os.Open(name)
+	if err != nil {
+		record.Err = err.Error()
+		return record
+	}
+	defer file.Close()
+
+	exif, err := helps.ReadEXIF(file)
+	if err != nil {
+		record.Err = err.Error()
+		return record
+	}
+	record.Row[1] = exif.Captured
+	if !exif.HasGPS {
+		record.Err = "no GPS coordinates found"
+		return record
+	}
+	record.Latitude, record.Longitude = exif.Latitude, exif.Longitude
+	record.Row[2] = strconv.FormatFloat(exif.Latitude, 'f', -1, 64)
+	record.Row[3] = strconv.FormatFloat(exif.Longitude, 'f', -1, 64)
+	return record
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strconv"
+
+	reverse "github.com/smartystreets/smartystreets-go-sdk/us-reverse-geo-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+func Points(from helps.Coordinate, results []reverse.Result) (points []helps.Point) {
+	for _, result := range EnrichAll(from, results) {
+		points = append(points, helps.Point{
+			Latitude:  result.Coordinate.Latitude,
+			Longitude: result.Coordinate.Longitude,
+			Name:      result.Address.Street + ", " + result.Address.City + ", " + result.Address.StateAbbreviation + " " + result.Address.ZIPCode,
+			Description: helps.Describe(
+				"street", result.Address.Street,
+				"city", result.Address.City,
+				"state_abbreviation", result.Address.StateAbbreviation,
+				"zipcode", result.Address.ZIPCode,
+				"distance", strconv.FormatFloat(result.Distance, 'f', -1, 64),
+				"distance_feet", strconv.FormatFloat(result.DistanceFeet, 'f', -1, 64),
+				"distance_miles", strconv.FormatFloat(result.DistanceMiles, 'f', -1, 64),
+				"bearing", strconv.FormatFloat(result.Bearing, 'f', -1, 64)+" ("+result.CompassPoint+")",
+				"accuracy", result.Coordinate.Accuracy,
+			),
+			Properties: map[string]interface{}{
+				"street":             result.Address.Street,
+				"city":               result.Address.City,
+				"state_abbreviation": result.Address.StateAbbreviation,
+				"zipcode":            result.Address.ZIPCode,
+				"distance":           result.Distance,
+				"distance_feet":      result.DistanceFeet,
+				"distance_miles":     result.DistanceMiles,
+				"bearing":            result.Bearing,
+				"compass_point":      result.CompassPoint,
+				"accuracy":           result.Coordinate.Accuracy,
+			},
+		})
+	}
+	return points
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+// SampleRecords turns grid points into records ready to reverse geocode.
+func SampleRecords(points []helps.Coordinate) (records []*Record) {
+	for _, point := range points {
+		records = append(records, &Record{
+			Latitude:  point.Latitude,
+			Longitude: point.Longitude,
+			Row:       []string{formatDegrees(point.Latitude), formatDegrees(point.Longitude)},
+		})
+	}
+	return records
+}
+
+// FoundAddress is one address found while sampling, along with the grid point closest to it.
+type FoundAddress struct {
+	Street            string
+	City              string
+	StateAbbreviation string
+	ZIPCode           string
+	Latitude          float64
+	Longitude         float64
+	Nearest           helps.Coordinate // the grid point closest to the address
+	Distance          float64          // from the nearest grid point, in meters
+	Hits              int              // how many grid points found the address
+}
+
+func (this *FoundAddress) key() string {
+	return strings.ToUpper(strings.Join([]string{this.Street, this.City, this.StateAbbreviation, this.ZIPCode}, "|"))
+}
+
+// UniqueAddresses gathers every result of every record, keeping one entry per address.
+func UniqueAddresses(records []*Record) (addresses []*FoundAddress) {
+	found := make(map[string]*FoundAddress)
+	for _, record := range records {
+		for _, result := range record.Results {
+			address := &FoundAddress{
+				Street:            result.Address.Street,
+				City:              result.Address.City,
+				StateAbbreviation: result.Address.StateAbbreviation,
+				ZIPCode:           result.Address.ZIPCode,
+				Latitude:          result.Coordinate.Latitude,
+				Longitude:         result.Coordinate.Longitude,
+				Nearest:           helps.Coordinate{Latitude: record.Latitude, Longitude: record.Longitude},
+				Distance:          result.Distance,
+			}
+			existing, seen := found[address.key()]
+			if !seen {
+				found[address.key()] = address
+				addresses = append(addresses, address)
+				existing = address
+			} else if address.Distance < existing.Distance {
+				existing.Nearest, existing.Distance = address.Nearest, address.Distance
+			}
+			existing.Hits++
+		}
+	}
+	sort.SliceStable(addresses, func(i, j int) bool {
+		return addresses[i].key() < addresses[j].key()
+	})
+	return addresses
+}
+
+func WriteAddresses(writer io.Writer, addresses []*FoundAddress) error {
+	table := &helps.Table{Header: []string{
+		"street", "city", "state_abbreviation", "zipcode", "latitude", "longitude",
+		"nearest_latitude", "nearest_longitude", "distance", "hits",
+	}}
+	for _, address := range addresses {
+		table.Rows = append(table.Rows, []string{
+			address.Street,
+			address.City,
+			address.StateAbbreviation,
+			address.ZIPCode,
+			formatDegrees(address.Latitude),
+			formatDegrees(address.Longitude),
+			formatDegrees(address.Nearest.Latitude),
+			formatDegrees(address.Nearest.Longitude),
+			strconv.FormatFloat(address.Distance, 'f', -1, 64),
+			strconv.Itoa(address.Hits),
+		})
+	}
+	return table.Write(writer)
+}
+
+func formatDegrees(degrees float64) string {
+	return strconv.FormatFloat(degrees, 'f', 6, 64)
+}
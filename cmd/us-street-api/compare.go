@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+
+	"github.com/mdwhatcott/smarty-cli"
+)
+
+var comparedStrategies = []street.MatchStrategy{
+	street.MatchStrict,
+	street.MatchRange,
+	street.MatchInvalid,
+}
+
+type StrategyComparison struct {
+	strategies []street.MatchStrategy
+	lookups    map[street.MatchStrategy][]*street.Lookup
+}
+
+// CompareMatchStrategies submits a copy of every lookup once per match strategy.
+func CompareMatchStrategies(client *street.Client, lookups []*street.Lookup) (*StrategyComparison, error) {
+	comparison := &StrategyComparison{
+		strategies: comparedStrategies,
+		lookups:    make(map[street.MatchStrategy][]*street.Lookup),
+	}
+	for _, strategy := range comparison.strategies {
+		var copied []*street.Lookup
+		for _, lookup := range lookups {
+			clone := *lookup
+			clone.Results = nil
+			clone.MatchStrategy = strategy
+			copied = append(copied, &clone)
+		}
+		if err := cli.SendStreetLookups(client, copied); err != nil {
+			return nil, fmt.Errorf("match strategy %s: %w", strategy, err)
+		}
+		comparison.lookups[strategy] = copied
+	}
+	return comparison, nil
+}
+
+func (this *StrategyComparison) String() string {
+	builder := new(strings.Builder)
+	for index, lookup := range this.lookups[this.strategies[0]] {
+		fmt.Fprintf(builder, "Input %d: %s\n", index, describeLookup(lookup))
+
+		writer := tabwriter.NewWriter(builder, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "  strategy\tcandidates\tdpv\tdelivery_line_1\tlast_line")
+		for _, strategy := range this.strategies {
+			candidates := this.lookups[strategy][index].Results
+			if len(candidates) == 0 {
+				fmt.Fprintf(writer, "  %s\t0\t-\t-\t-\n", strategy)
+				continue
+			}
+			for i, candidate := range candidates {
+				label, count := "", ""
+				if i == 0 {
+					label, count = string(strategy), fmt.Sprint(len(candidates))
+				}
+				fmt.Fprintf(writer, "  %s\t%s\t%s\t%s\t%s\n", label, count,
+					orDash(candidate.Analysis.DPVMatchCode), candidate.DeliveryLine1, candidate.LastLine)
+			}
+		}
+		_ = writer.Flush()
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func describeLookup(lookup *street.Lookup) string {
+	return joinNonBlank(lookup.Street, lookup.Street2, lookup.Secondary,
+		lookup.City, lookup.State, lookup.ZIPCode, lookup.LastLine)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+func DumpDiffs(lookups []*street.Lookup, colored bool) string {
+	builder := new(strings.Builder)
+	for index, lookup := range lookups {
+		if len(lookup.Results) == 0 {
+			fmt.Fprintf(builder, "Input %d: no candidates\n\n", index)
+			continue
+		}
+		for _, candidate := range lookup.Results {
+			fmt.Fprintf(builder, "Input %d, candidate %d:\n", index, candidate.CandidateIndex)
+			builder.WriteString(orNoChanges(helps.DumpDiff(diffFields(lookup, candidate), colored)))
+			builder.WriteString("\n")
+		}
+	}
+	return builder.String()
+}
+
+func orNoChanges(diff string) string {
+	if diff == "" {
+		return "No changes\n"
+	}
+	return diff
+}
+
+func diffFields(lookup *street.Lookup, candidate *street.Candidate) []helps.FieldDiff {
+	components := candidate.Components
+	return []helps.FieldDiff{
+		{Field: "addressee", Input: lookup.Addressee, Output: candidate.Addressee},
+		{Field: "urbanization", Input: lookup.Urbanization, Output: components.Urbanization},
+		{Field: "street", Input: lookup.Street, Output: candidate.DeliveryLine1},
+		{Field: "street2", Input: lookup.Street2, Output: candidate.DeliveryLine2},
+		{Field: "secondary", Input: lookup.Secondary, Output: joinNonBlank(components.SecondaryDesignator, components.SecondaryNumber)},
+		{Field: "city", Input: lookup.City, Output: components.CityName},
+		{Field: "state", Input: lookup.State, Output: components.StateAbbreviation},
+		{Field: "zipcode", Input: lookup.ZIPCode, Output: components.ZIPCode},
+		{Field: "plus4_code", Output: components.Plus4Code},
+		{Field: "lastline", Input: lookup.LastLine, Output: lastLineOrBlank(lookup, candidate)},
+	}
+}
+
+// The last line is only worth comparing when one was actually submitted;
+// otherwise it duplicates the city/state/zipcode rows.
+func lastLineOrBlank(lookup *street.Lookup, candidate *street.Candidate) string {
+	if lookup.LastLine == "" {
+		return ""
+	}
+	return candidate.LastLine
+}
+
+func joinNonBlank(values ...string) string {
+	var nonBlank []string
+	for _, value := range values {
+		if value != "" {
+			nonBlank = append(nonBlank, value)
+		}
+	}
+	return strings.Join(nonBlank, " ")
+}
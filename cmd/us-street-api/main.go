@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -27,18 +28,39 @@ func main() {
 		wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
 		wireup.DebugHTTPOutput(),
 	)
-	batch := inputs.PopulateBatch()
+	lookups := inputs.PopulateLookups()
 
-	if err := client.SendBatch(batch); err != nil {
+	if inputs.compareMatch {
+		comparison, err := CompareMatchStrategies(client, lookups)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Println("Match Strategy Comparison:")
+		fmt.Print(comparison)
+		return
+	}
+
+	if err := cli.SendStreetLookups(client, lookups); err != nil {
 		log.Fatal(err)
 	}
 
+	if inputs.Splitting() {
+		inputs.WriteSplit()
+		return
+	}
+
 	var candidates []*street.Candidate
-	for _, record := range batch.Records() {
-		candidates = append(candidates, record.Results...)
+	for _, lookup := range lookups {
+		candidates = append(candidates, lookup.Results...)
 	}
 	log.Println("Formatted Result:")
-	fmt.Println(helps.DumpJSON(candidates))
+	if dump, found := helps.PointFormats[inputs.format]; found {
+		fmt.Println(dump(Points(candidates)))
+	} else if inputs.diff {
+		fmt.Print(DumpDiffs(lookups, inputs.color))
+	} else {
+		fmt.Println(helps.DumpJSON(candidates))
+	}
 }
 
 ///////////////////
@@ -47,8 +69,9 @@ type Inputs struct {
 	*cli.Inputs
 
 	baseURL  string
-	licenses string
+	licenses *cli.ListFlag
 
+	address           string
 	addressee         string
 	urbanization      string
 	street1           string
@@ -62,13 +85,27 @@ type Inputs struct {
 	maxCandidateCount int
 	matchStrategy     string
 
-	lookup *street.Lookup
+	format       string
+	diff         bool
+	color        bool
+	compareMatch bool
+
+	input        string
+	outMatched   string
+	outAmbiguous string
+	outUnmatched string
+	outRejects   string
+
+	table   *helps.Table
+	records []*Record
+	lookup  *street.Lookup
 }
 
 func NewInputs() *Inputs {
 	this := &Inputs{
-		Inputs: cli.NewInputs(),
-		lookup: new(street.Lookup),
+		Inputs:   cli.NewInputs(),
+		licenses: cli.NewListFlag(',', "us-core-cloud"),
+		lookup:   new(street.Lookup),
 	}
 	this.flags()
 	return this
@@ -76,7 +113,8 @@ func NewInputs() *Inputs {
 
 func (this *Inputs) flags() {
 	flag.StringVar(&this.baseURL, "baseURL", os.Getenv("SMARTY_US_STREET_API"), "The URL")
-	flag.StringVar(&this.licenses, "licenses", "us-core-cloud", "The licenses")
+	flag.Var(this.licenses, "licenses", "The licenses (separated by ',', repeatable)")
+	flag.StringVar(&this.address, "address", "", "A single-line freeform address, sent as the street field (US Street API). Use '-' to read one freeform address per line from stdin.")
 	flag.StringVar(&this.addressee, "addressee", "", "The Addresses (US Street API)")
 	flag.StringVar(&this.urbanization, "urbanization", "", "The Urbanization (US Street API)")
 	flag.StringVar(&this.street1, "street", "", "The Street1 (US Street API)")
@@ -89,11 +127,89 @@ func (this *Inputs) flags() {
 	flag.StringVar(&this.inputID, "input_id", "", "The Input ID (US Street API, US ZIP Code API)")
 	flag.IntVar(&this.maxCandidateCount, "candidates", 10, "The max candidate count (US Street API)")
 	flag.StringVar(&this.matchStrategy, "match", string(street.MatchStrict), "The Match Strategy (US Street API)")
+	flag.StringVar(&this.input, "input", "", "A CSV file of lookups ('-' for stdin) with a header row naming the fields (street, city, state, zipcode, etc...).")
+	flag.StringVar(&this.outMatched, "out-matched", "", "With -input, the CSV file to receive records with exactly one confident candidate.")
+	flag.StringVar(&this.outAmbiguous, "out-ambiguous", "", "With -input, the CSV file to receive records with multiple candidates.")
+	flag.StringVar(&this.outUnmatched, "out-unmatched", "", "With -input, the CSV file to receive records with no candidate, or with a single candidate DPV did not confirm (dpv_match_code N or blank).")
+	flag.StringVar(&this.outRejects, "out-rejects", "", "With -input, the CSV file to receive records rejected before sending (no street provided).")
+	flag.BoolVar(&this.compareMatch, "compare-match", false, "Submit the lookups under the strict, range and invalid match strategies and compare the results side by side.")
+	flag.StringVar(&this.format, "format", helps.FormatJSON, "The output format (choose from: "+helps.PointFormatNames()+").")
+	flag.BoolVar(&this.diff, "diff", false, "Show each input field lined up against the standardized candidate instead of JSON.")
+	flag.BoolVar(&this.color, "color", helps.IsTerminal(os.Stdout), "Colorize the -diff output (defaults to true when writing to a terminal).")
 	this.ParseFlags()
+
+	if !helps.IsPointFormat(this.format) {
+		log.Fatal("Unrecognized -format value:", this.format)
+	}
+	if this.Splitting() && this.input == "" {
+		log.Fatal("The -out-* options require -input.")
+	}
+	if this.address != "" && this.street1 != "" {
+		log.Fatal("The -address option can't be combined with -street.")
+	}
+	if this.address != "" && this.input != "" {
+		log.Fatal("The -address option can't be combined with -input.")
+	}
 }
 
 func (this *Inputs) Licenses() []string {
-	return strings.Split(this.licenses, ",")
+	return this.licenses.Values
+}
+
+func (this *Inputs) PopulateLookups() (lookups []*street.Lookup) {
+	if this.input != "" {
+		return this.populateLookupsFromTable()
+	}
+	if this.address == "-" {
+		return this.populateLookupsFromLines()
+	}
+	return this.PopulateBatch().Records()
+}
+
+func (this *Inputs) populateLookupsFromLines() (lookups []*street.Lookup) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for line := 1; scanner.Scan(); line++ {
+		address := strings.TrimSpace(scanner.Text())
+		if address == "" {
+			continue
+		}
+		lookups = append(lookups, &street.Lookup{
+			Street:        address,
+			InputID:       strconv.Itoa(line),
+			MaxCandidates: this.maxCandidateCount,
+			MatchStrategy: street.MatchStrategy(this.matchStrategy),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	if len(lookups) == 0 {
+		log.Fatal("No addresses provided.")
+	}
+	return lookups
+}
+
+func (this *Inputs) populateLookupsFromTable() (lookups []*street.Lookup) {
+	var err error
+	this.table, err = helps.ReadTableFile(this.input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, row := range this.table.Rows {
+		record := NewRecord(row, cli.NewStreetLookup(this.table.Values(row)))
+		if record.Lookup.MaxCandidates == 0 {
+			record.Lookup.MaxCandidates = this.maxCandidateCount
+		}
+		if record.Lookup.MatchStrategy == "" {
+			record.Lookup.MatchStrategy = street.MatchStrategy(this.matchStrategy)
+		}
+		this.records = append(this.records, record)
+		if record.Rejection == "" {
+			lookups = append(lookups, record.Lookup)
+		}
+	}
+	log.Printf("Read %d records (%d rejected) from: %s", len(this.records), len(this.records)-len(lookups), this.input)
+	return lookups
 }
 
 func (this *Inputs) PopulateBatch() *street.Batch {
@@ -137,23 +253,16 @@ func (this *Inputs) assembleLookup() *street.Lookup {
 	return this.lookup
 }
 func (this *Inputs) assembleLookupFromQueryString(values url.Values) {
-	this.lookup.Street = values.Get("street")
-	this.lookup.Street2 = values.Get("street2")
-	this.lookup.City = values.Get("city")
-	this.lookup.State = values.Get("state")
-	this.lookup.ZIPCode = values.Get("zipcode")
-	this.lookup.LastLine = values.Get("lastline")
-	this.lookup.Addressee = values.Get("addressee")
-	this.lookup.Urbanization = values.Get("urbanization")
-	this.lookup.Secondary = values.Get("secondary")
-	this.lookup.MatchStrategy = street.MatchStrategy(values.Get("match"))
-	this.lookup.MaxCandidates, _ = strconv.Atoi(values.Get("candidates"))
+	this.lookup = cli.NewStreetLookup(values)
 }
 
 func (this *Inputs) assembleLookupFromFlags() {
 	this.lookup.Addressee = this.addressee
 	this.lookup.Urbanization = this.urbanization
 	this.lookup.Street = this.street1
+	if this.address != "" {
+		this.lookup.Street = this.address
+	}
 	this.lookup.Street2 = this.street2
 	this.lookup.LastLine = this.lastLine
 	this.lookup.Secondary = this.secondary
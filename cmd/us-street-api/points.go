@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+// Points skips candidates that weren't geocoded.
+func Points(candidates []*street.Candidate) (points []helps.Point) {
+	for _, candidate := range candidates {
+		metadata := candidate.Metadata
+		if metadata.Latitude == 0 && metadata.Longitude == 0 {
+			continue
+		}
+		points = append(points, helps.Point{
+			Latitude:  metadata.Latitude,
+			Longitude: metadata.Longitude,
+			Name:      joinNonBlank(candidate.DeliveryLine1, candidate.DeliveryLine2, candidate.LastLine),
+			Description: helps.Describe(
+				"primary_number", candidate.Components.PrimaryNumber,
+				"street_predirection", candidate.Components.StreetPredirection,
+				"street_name", candidate.Components.StreetName,
+				"street_suffix", candidate.Components.StreetSuffix,
+				"street_postdirection", candidate.Components.StreetPostdirection,
+				"secondary_designator", candidate.Components.SecondaryDesignator,
+				"secondary_number", candidate.Components.SecondaryNumber,
+				"city_name", candidate.Components.CityName,
+				"state_abbreviation", candidate.Components.StateAbbreviation,
+				"zipcode", candidate.Components.ZIPCode,
+				"plus4_code", candidate.Components.Plus4Code,
+			),
+			Properties: map[string]interface{}{
+				"input_id":               candidate.InputID,
+				"input_index":            candidate.InputIndex,
+				"candidate_index":        candidate.CandidateIndex,
+				"delivery_line_1":        candidate.DeliveryLine1,
+				"last_line":              candidate.LastLine,
+				"delivery_point_barcode": candidate.DeliveryPointBarcode,
+				"dpv_match_code":         candidate.Analysis.DPVMatchCode,
+				"precision":              metadata.Precision,
+				"county_name":            metadata.CountyName,
+				"rdi":                    metadata.RDI,
+			},
+		})
+	}
+	return points
+}
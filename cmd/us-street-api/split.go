@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+const (
+	classMatched   = "matched"
+	classAmbiguous = "ambiguous"
+	classUnmatched = "unmatched"
+	classRejected  = "rejected"
+)
+
+// Record pairs an input row with the lookup built from it.
+type Record struct {
+	Row       []string
+	Lookup    *street.Lookup
+	Rejection string // why the record was never sent (blank when it was)
+}
+
+func NewRecord(row []string, lookup *street.Lookup) *Record {
+	record := &Record{Row: row, Lookup: lookup}
+	if strings.TrimSpace(lookup.Street) == "" {
+		record.Rejection = "no street provided"
+	}
+	return record
+}
+
+func (this *Record) Classify() (class, reason string) {
+	candidates := this.Lookup.Results
+	switch {
+	case this.Rejection != "":
+		return classRejected, this.Rejection
+	case len(candidates) == 0:
+		return classUnmatched, "no candidates"
+	case len(candidates) > 1:
+		return classAmbiguous, fmt.Sprintf("%d candidates", len(candidates))
+	case !confidentMatchCodes[candidates[0].Analysis.DPVMatchCode]:
+		return classUnmatched, "candidate not confirmed (dpv_match_code=" + candidates[0].Analysis.DPVMatchCode + ")"
+	default:
+		return classMatched, "dpv_match_code=" + candidates[0].Analysis.DPVMatchCode
+	}
+}
+
+// Confirmed by DPV: to the unit (Y), by ignoring the unit (S), or missing a required unit (D).
+var confidentMatchCodes = map[string]bool{"Y": true, "S": true, "D": true}
+
+func (this *Inputs) Splitting() bool {
+	return len(this.splitPaths()) > 0
+}
+
+func (this *Inputs) splitPaths() map[string]string {
+	paths := make(map[string]string)
+	for class, path := range map[string]string{
+		classMatched:   this.outMatched,
+		classAmbiguous: this.outAmbiguous,
+		classUnmatched: this.outUnmatched,
+		classRejected:  this.outRejects,
+	} {
+		if path != "" {
+			paths[class] = path
+		}
+	}
+	return paths
+}
+
+// WriteSplit writes each record, with its original columns followed by a summary
+// of the first candidate and the reason for its classification, to the file for its class.
+func (this *Inputs) WriteSplit() {
+	header := append(append([]string{}, this.table.Header...), splitColumns...)
+	tables := make(map[string]*helps.Table)
+	for class := range this.splitPaths() {
+		tables[class] = &helps.Table{Header: header}
+	}
+
+	for _, record := range this.records {
+		class, reason := record.Classify()
+		table, found := tables[class]
+		if !found {
+			continue
+		}
+		row := make([]string, len(this.table.Header))
+		copy(row, record.Row)
+		row = append(row, summarizeFirstCandidate(record.Lookup.Results)...)
+		table.Rows = append(table.Rows, append(row, class, reason))
+	}
+
+	for class, path := range this.splitPaths() {
+		if err := helps.WriteTableFile(path, tables[class]); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Wrote %d %s records to: %s", len(tables[class].Rows), class, path)
+	}
+}
+
+var splitColumns = []string{"candidates", "delivery_line_1", "last_line", "dpv_match_code", "classification", "reason"}
+
+func summarizeFirstCandidate(candidates []*street.Candidate) []string {
+	if len(candidates) == 0 {
+		return []string{"0", "", "", ""}
+	}
+	first := candidates[0]
+	return []string{fmt.Sprint(len(candidates)), first.DeliveryLine1, first.LastLine, first.Analysis.DPVMatchCode}
+}
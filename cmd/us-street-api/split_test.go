@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+)
+
+func candidate(dpvMatchCode string) *street.Candidate {
+	return &street.Candidate{Analysis: street.Analysis{DPVMatchCode: dpvMatchCode}}
+}
+
+func TestRecordClassify(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		street     string
+		candidates []*street.Candidate
+		class      string
+		reason     string
+	}{
+		{name: "confirmed", street: "1 Main", candidates: []*street.Candidate{candidate("Y")}, class: classMatched, reason: "dpv_match_code=Y"},
+		{name: "unit ignored", street: "1 Main", candidates: []*street.Candidate{candidate("S")}, class: classMatched, reason: "dpv_match_code=S"},
+		{name: "unit missing", street: "1 Main", candidates: []*street.Candidate{candidate("D")}, class: classMatched, reason: "dpv_match_code=D"},
+		{name: "several candidates", street: "1 Main", candidates: []*street.Candidate{candidate("Y"), candidate("Y")}, class: classAmbiguous, reason: "2 candidates"},
+		{name: "no candidates", street: "1 Main", class: classUnmatched, reason: "no candidates"},
+		{name: "not confirmed", street: "1 Main", candidates: []*street.Candidate{candidate("N")}, class: classUnmatched, reason: "candidate not confirmed (dpv_match_code=N)"},
+		{name: "not checked", street: "1 Main", candidates: []*street.Candidate{candidate("")}, class: classUnmatched, reason: "candidate not confirmed (dpv_match_code=)"},
+		{name: "no street", street: "  ", class: classRejected, reason: "no street provided"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			lookup := &street.Lookup{Street: test.street}
+			record := NewRecord(nil, lookup)
+			lookup.Results = test.candidates
+			class, reason := record.Classify()
+			if class != test.class || reason != test.reason {
+				t.Errorf("got (%s, %q), want (%s, %q)", class, reason, test.class, test.reason)
+			}
+		})
+	}
+}
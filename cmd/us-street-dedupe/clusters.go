@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+const (
+	keepFirst    = "first"
+	keepComplete = "complete"
+)
+
+var keyFields = map[string]func(*street.Candidate) string{
+	"delivery_point_barcode": func(c *street.Candidate) string { return c.DeliveryPointBarcode },
+	"addressee":              func(c *street.Candidate) string { return c.Addressee },
+	"delivery_line_1":        func(c *street.Candidate) string { return c.DeliveryLine1 },
+	"delivery_line_2":        func(c *street.Candidate) string { return c.DeliveryLine2 },
+	"last_line":              func(c *street.Candidate) string { return c.LastLine },
+	"primary_number":         func(c *street.Candidate) string { return c.Components.PrimaryNumber },
+	"street_name":            func(c *street.Candidate) string { return c.Components.StreetName },
+	"secondary_number":       func(c *street.Candidate) string { return c.Components.SecondaryNumber },
+	"city_name":              func(c *street.Candidate) string { return c.Components.CityName },
+	"state_abbreviation":     func(c *street.Candidate) string { return c.Components.StateAbbreviation },
+	"zipcode":                func(c *street.Candidate) string { return c.Components.ZIPCode },
+	"plus4_code":             func(c *street.Candidate) string { return c.Components.Plus4Code },
+}
+
+type Cluster struct {
+	Key  string
+	Rows []int // zero-based indexes into the input rows, in input order
+}
+
+type Clusters struct {
+	all       []*Cluster
+	unmatched []int
+}
+
+// GroupByKey groups the lookups by the standardized key of their first candidate.
+// Lookups without a candidate (or whose candidate leaves every key field blank)
+// can't be compared, so each one is kept in a cluster of its own.
+func GroupByKey(lookups []*street.Lookup, key []string) *Clusters {
+	clusters := new(Clusters)
+	byKey := make(map[string]*Cluster)
+	for index, lookup := range lookups {
+		value, ok := "", len(lookup.Results) > 0
+		if ok {
+			value, ok = standardizedKey(lookup.Results[0], key)
+		}
+		if !ok {
+			clusters.unmatched = append(clusters.unmatched, index)
+			clusters.all = append(clusters.all, &Cluster{Rows: []int{index}})
+			continue
+		}
+		cluster, found := byKey[value]
+		if !found {
+			cluster = &Cluster{Key: value}
+			byKey[value] = cluster
+			clusters.all = append(clusters.all, cluster)
+		}
+		cluster.Rows = append(cluster.Rows, index)
+	}
+	return clusters
+}
+
+func standardizedKey(candidate *street.Candidate, key []string) (value string, ok bool) {
+	var values []string
+	for _, field := range key {
+		value := strings.ToUpper(strings.TrimSpace(keyFields[field](candidate)))
+		ok = ok || value != ""
+		values = append(values, value)
+	}
+	return strings.Join(values, " | "), ok
+}
+
+func (this *Clusters) Duplicates() (duplicates []*Cluster) {
+	for _, cluster := range this.all {
+		if len(cluster.Rows) > 1 {
+			duplicates = append(duplicates, cluster)
+		}
+	}
+	return duplicates
+}
+
+// Survivors returns the index of one row from every cluster, in input order.
+func (this *Clusters) Survivors(table *helps.Table, keep string) (survivors []int) {
+	for _, cluster := range this.all {
+		survivor := cluster.Rows[0]
+		if keep == keepComplete {
+			for _, index := range cluster.Rows[1:] {
+				if completeness(table.Rows[index]) > completeness(table.Rows[survivor]) {
+					survivor = index
+				}
+			}
+		}
+		survivors = append(survivors, survivor)
+	}
+	sort.Ints(survivors)
+	return survivors
+}
+
+func completeness(row []string) (filled int) {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			filled++
+		}
+	}
+	return filled
+}
+
+// String reports each duplicate cluster with 1-based row numbers (not counting the header row).
+func (this *Clusters) String() string {
+	builder := new(strings.Builder)
+	for _, cluster := range this.Duplicates() {
+		var rows []string
+		for _, index := range cluster.Rows {
+			rows = append(rows, fmt.Sprint(index+1))
+		}
+		fmt.Fprintf(builder, "%s\n  rows: %s\n", cluster.Key, strings.Join(rows, ", "))
+	}
+	if len(this.unmatched) > 0 {
+		var rows []string
+		for _, index := range this.unmatched {
+			rows = append(rows, fmt.Sprint(index+1))
+		}
+		fmt.Fprintf(builder, "(no candidate key)\n  rows: %s\n", strings.Join(rows, ", "))
+	}
+	return builder.String()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+func matched(barcode, deliveryLine string) *street.Lookup {
+	return &street.Lookup{Results: []*street.Candidate{{DeliveryPointBarcode: barcode, DeliveryLine1: deliveryLine}}}
+}
+
+func unmatched() *street.Lookup {
+	return &street.Lookup{}
+}
+
+func clusterRows(clusters []*Cluster) (rows [][]int) {
+	for _, cluster := range clusters {
+		rows = append(rows, cluster.Rows)
+	}
+	return rows
+}
+
+func TestGroupByKey(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		lookups    []*street.Lookup
+		key        []string
+		duplicates [][]int
+		survivors  []int
+	}{
+		{
+			name:       "distinct",
+			lookups:    []*street.Lookup{matched("1", ""), matched("2", "")},
+			key:        []string{"delivery_point_barcode"},
+			duplicates: nil,
+			survivors:  []int{0, 1},
+		},
+		{
+			name:       "duplicates keep the first row",
+			lookups:    []*street.Lookup{matched("1", ""), matched("2", ""), matched("1", "")},
+			key:        []string{"delivery_point_barcode"},
+			duplicates: [][]int{{0, 2}},
+			survivors:  []int{0, 1},
+		},
+		{
+			name:       "key is case insensitive",
+			lookups:    []*street.Lookup{matched("", "1 Main St"), matched("", "1 MAIN ST")},
+			key:        []string{"delivery_line_1"},
+			duplicates: [][]int{{0, 1}},
+			survivors:  []int{0},
+		},
+		{
+			name:       "rows without candidates are never merged",
+			lookups:    []*street.Lookup{unmatched(), matched("1", ""), unmatched(), unmatched()},
+			key:        []string{"delivery_point_barcode"},
+			duplicates: nil,
+			survivors:  []int{0, 1, 2, 3},
+		},
+		{
+			name:       "candidates with a blank key are never merged",
+			lookups:    []*street.Lookup{matched("", "1 Main St"), matched("", "2 Main St")},
+			key:        []string{"delivery_point_barcode"},
+			duplicates: nil,
+			survivors:  []int{0, 1},
+		},
+		{
+			name:       "partially blank keys still compare",
+			lookups:    []*street.Lookup{matched("", "1 Main St"), matched("", "1 Main St")},
+			key:        []string{"delivery_point_barcode", "delivery_line_1"},
+			duplicates: [][]int{{0, 1}},
+			survivors:  []int{0},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			clusters := GroupByKey(test.lookups, test.key)
+			if actual := clusterRows(clusters.Duplicates()); !reflect.DeepEqual(actual, test.duplicates) {
+				t.Errorf("duplicates: got %v, want %v", actual, test.duplicates)
+			}
+			table := &helps.Table{Rows: make([][]string, len(test.lookups))}
+			if actual := clusters.Survivors(table, keepFirst); !reflect.DeepEqual(actual, test.survivors) {
+				t.Errorf("survivors: got %v, want %v", actual, test.survivors)
+			}
+		})
+	}
+}
+
+func TestSurvivorsKeepComplete(t *testing.T) {
+	lookups := []*street.Lookup{matched("1", ""), matched("2", ""), matched("1", ""), unmatched()}
+	table := &helps.Table{Rows: [][]string{
+		{"1 Main", "", ""},
+		{"2 Main", "Provo", "UT"},
+		{"1 Main", "Provo", "UT"},
+		{"nowhere", "", ""},
+	}}
+	clusters := GroupByKey(lookups, []string{"delivery_point_barcode"})
+	if actual, expected := clusters.Survivors(table, keepComplete), []int{1, 2, 3}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
+
+func TestClustersString(t *testing.T) {
+	lookups := []*street.Lookup{matched("1", ""), unmatched(), matched("1", ""), unmatched()}
+	clusters := GroupByKey(lookups, []string{"delivery_point_barcode"})
+	expected := "1\n  rows: 1, 3\n(no candidate key)\n  rows: 2, 4\n"
+	if actual := clusters.String(); actual != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", actual, expected)
+	}
+}
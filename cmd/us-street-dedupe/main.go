@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+	"github.com/smartystreets/smartystreets-go-sdk/wireup"
+
+	"github.com/mdwhatcott/smarty-cli"
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+func main() {
+	log.SetFlags(log.Lmicroseconds)
+
+	inputs := NewInputs()
+	client := wireup.BuildUSStreetAPIClient(
+		wireup.CustomBaseURL(inputs.baseURL),
+		wireup.WithLicenses(inputs.Licenses()...),
+		wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
+	)
+	table := inputs.ReadTable()
+
+	var lookups []*street.Lookup
+	for _, row := range table.Rows {
+		lookup := cli.NewStreetLookup(table.Values(row))
+		lookup.MaxCandidates = 1
+		lookups = append(lookups, lookup)
+	}
+	if err := cli.SendStreetLookups(client, lookups); err != nil {
+		log.Fatal(err)
+	}
+
+	clusters := GroupByKey(lookups, inputs.Key())
+	log.Printf("Found %d duplicate clusters among %d rows.", len(clusters.Duplicates()), len(table.Rows))
+	fmt.Print(clusters)
+
+	if inputs.output == "" {
+		return
+	}
+	deduplicated := &helps.Table{Header: table.Header}
+	for _, index := range clusters.Survivors(table, inputs.keep) {
+		deduplicated.Rows = append(deduplicated.Rows, table.Rows[index])
+	}
+	inputs.WriteTable(deduplicated)
+}
+
+///////////////////
+
+type Inputs struct {
+	*cli.Inputs
+
+	baseURL  string
+	licenses *cli.ListFlag
+
+	input  string
+	output string
+	key    string
+	keep   string
+}
+
+func NewInputs() *Inputs {
+	this := &Inputs{
+		Inputs:   cli.NewInputs(),
+		licenses: cli.NewListFlag(',', "us-core-cloud"),
+	}
+	this.flags()
+	return this
+}
+
+func (this *Inputs) flags() {
+	var keys []string
+	for key := range keyFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	flag.StringVar(&this.baseURL, "baseURL", os.Getenv("SMARTY_US_STREET_API"), "The URL")
+	flag.Var(this.licenses, "licenses", "The licenses (separated by ',', repeatable)")
+	flag.StringVar(&this.input, "input", "-", "The CSV file of addresses (with a header row naming the US Street API fields: street, city, state, zipcode, etc...). Defaults to stdin.")
+	flag.StringVar(&this.output, "output", "", "Where to write the deduplicated CSV file ('-' for stdout). When blank only the duplicate report is written.")
+	flag.StringVar(&this.key, "key", "delivery_point_barcode", "Comma-separated standardized fields that identify a duplicate (choose from: "+strings.Join(keys, ", ")+").")
+	flag.StringVar(&this.keep, "keep", keepFirst, "Which row of each duplicate cluster to keep in the -output file ('"+keepFirst+"' or '"+keepComplete+"').")
+	this.ParseFlags()
+
+	if this.keep != keepFirst && this.keep != keepComplete {
+		log.Fatal("Unrecognized -keep value:", this.keep)
+	}
+}
+
+func (this *Inputs) Licenses() []string {
+	return this.licenses.Values
+}
+
+func (this *Inputs) Key() []string {
+	fields := strings.Split(this.key, ",")
+	for i, field := range fields {
+		fields[i] = strings.TrimSpace(field)
+		if _, found := keyFields[fields[i]]; !found {
+			log.Fatal("Unrecognized -key field:", fields[i])
+		}
+	}
+	return fields
+}
+
+func (this *Inputs) ReadTable() *helps.Table {
+	table, err := helps.ReadTableFile(this.input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return table
+}
+
+func (this *Inputs) WriteTable(table *helps.Table) {
+	if err := helps.WriteTableFile(this.output, table); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Wrote %d rows to: %s", len(table.Rows), this.output)
+}
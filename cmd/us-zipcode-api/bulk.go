@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-zipcode-api"
+
+	"github.com/mdwhatcott/smarty-cli"
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+const (
+	inputCSV    = "csv"
+	inputNDJSON = "ndjson"
+)
+
+// InputFormat is the format named, or else the one implied by the file extension (CSV by default).
+func InputFormat(format, path string) string {
+	if format != "" {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return inputNDJSON
+	default:
+		return inputCSV
+	}
+}
+
+// Rows are the input records (as a table, whatever the input format) and their lookups, in the same order.
+type Rows struct {
+	Table   *helps.Table
+	Lookups []*zipcode.Lookup
+}
+
+// ReadRows reads city/state/zipcode records, taking each lookup's input ID from the idColumn
+// (or from the row number, starting at 1, when the input has no such column).
+func ReadRows(reader io.Reader, format, idColumn string) (*Rows, error) {
+	var table *helps.Table
+	var err error
+	switch format {
+	case inputCSV:
+		table, err = helps.ReadTable(reader)
+	case inputNDJSON:
+		table, err = readNDJSON(reader)
+	default:
+		return nil, fmt.Errorf("unrecognized input format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Column(idColumn) < 0 {
+		table.Header = append(table.Header, idColumn)
+		for i, row := range table.Rows {
+			padded := make([]string, len(table.Header))
+			copy(padded, row)
+			padded[len(padded)-1] = strconv.Itoa(i + 1)
+			table.Rows[i] = padded
+		}
+	}
+	rows := &Rows{Table: table}
+	for _, row := range table.Rows {
+		values := table.Values(row)
+		values.Set("input_id", table.Get(row, idColumn))
+		rows.Lookups = append(rows.Lookups, cli.NewZIPCodeLookup(values))
+	}
+	return rows, nil
+}
+
+// readNDJSON reads one JSON object per line into a table whose columns are every key seen
+// (ordered by first appearance, then alphabetically within a line, ignoring case).
+func readNDJSON(reader io.Reader) (*helps.Table, error) {
+	table := new(helps.Table)
+	var records []map[int]string // values by column
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &object); err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		var keys []string
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		record := make(map[int]string)
+		for _, key := range keys {
+			column := table.Column(key)
+			if column < 0 {
+				column = len(table.Header)
+				table.Header = append(table.Header, key)
+			}
+			record[column] = stringify(object[key])
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		row := make([]string, len(table.Header))
+		for column, value := range record {
+			row[column] = value
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+func stringify(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		encoded, _ := json.Marshal(typed)
+		return string(encoded)
+	}
+}
+
+///////////////////
+
+var resultColumns = []string{
+	"status", "reason",
+	"result_city", "result_state_abbreviation", "mailable_city", "city_states",
+	"result_zipcode", "zipcode_type", "default_city", "county_fips", "county_name", "latitude", "longitude", "precision", "zipcodes",
+}
+
+// Write joins each input row to its result: the first city/state and ZIP Code in their own columns,
+// and every city/state ("City, ST") and ZIP Code in the ';' separated city_states and zipcodes columns.
+func (this *Rows) Write(writer io.Writer) error {
+	output := &helps.Table{Header: append(append([]string{}, this.Table.Header...), resultColumns...)}
+	for i, row := range this.Table.Rows {
+		padded := make([]string, len(this.Table.Header))
+		copy(padded, row)
+		output.Rows = append(output.Rows, append(padded, resultRow(this.Lookups[i].Result)...))
+	}
+	return output.Write(writer)
+}
+
+func resultRow(result *zipcode.Result) []string {
+	row := make([]string, len(resultColumns))
+	if result == nil {
+		return row
+	}
+	row[0], row[1] = result.Status, result.Reason
+
+	var cityStates []string
+	for _, cityState := range result.CityStates {
+		cityStates = append(cityStates, cityState.City+", "+cityState.StateAbbreviation)
+	}
+	if len(result.CityStates) > 0 {
+		first := result.CityStates[0]
+		row[2], row[3], row[4] = first.City, first.StateAbbreviation, strconv.FormatBool(first.MailableCity)
+	}
+	row[5] = strings.Join(cityStates, ";")
+
+	var zipCodes []string
+	for _, zip := range result.ZIPCodes {
+		zipCodes = append(zipCodes, zip.ZIPCode)
+	}
+	if len(result.ZIPCodes) > 0 {
+		first := result.ZIPCodes[0]
+		row[6], row[7], row[8] = first.ZIPCode, first.ZIPCodeType, first.DefaultCity
+		row[9], row[10] = first.CountyFIPS, first.CountyName
+		row[11] = strconv.FormatFloat(first.Latitude, 'f', -1, 64)
+		row[12] = strconv.FormatFloat(first.Longitude, 'f', -1, 64)
+		row[13] = first.Precision
+	}
+	row[14] = strings.Join(zipCodes, ";")
+	return row
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-zipcode-api"
+)
+
+func TestInputFormat(t *testing.T) {
+	for _, test := range []struct{ format, path, expected string }{
+		{format: "", path: "records.csv", expected: inputCSV},
+		{format: "", path: "records.NDJSON", expected: inputNDJSON},
+		{format: "", path: "records.jsonl", expected: inputNDJSON},
+		{format: "", path: "-", expected: inputCSV},
+		{format: inputNDJSON, path: "records.csv", expected: inputNDJSON},
+	} {
+		if actual := InputFormat(test.format, test.path); actual != test.expected {
+			t.Errorf("(%q, %q): got %s, want %s", test.format, test.path, actual, test.expected)
+		}
+	}
+}
+
+func TestReadRowsCSV(t *testing.T) {
+	input := "input_id,city,state,zipcode\n" +
+		"a,Provo,UT,\n" +
+		"b,,,84601\n"
+	rows, err := ReadRows(strings.NewReader(input), inputCSV, "input_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*zipcode.Lookup{
+		{InputID: "a", City: "Provo", State: "UT"},
+		{InputID: "b", ZIPCode: "84601"},
+	}
+	assertLookups(t, rows.Lookups, expected)
+	if expected := []string{"input_id", "city", "state", "zipcode"}; !reflect.DeepEqual(rows.Table.Header, expected) {
+		t.Errorf("header: got %v, want %v", rows.Table.Header, expected)
+	}
+}
+
+func TestReadRowsRowNumberInputID(t *testing.T) {
+	input := "city,state,zipCode\n" +
+		"Provo,UT\n" + // short row
+		",,84601\n"
+	rows, err := ReadRows(strings.NewReader(input), inputCSV, "input_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*zipcode.Lookup{
+		{InputID: "1", City: "Provo", State: "UT"},
+		{InputID: "2", ZIPCode: "84601"},
+	}
+	assertLookups(t, rows.Lookups, expected)
+	if expected := []string{"city", "state", "zipCode", "input_id"}; !reflect.DeepEqual(rows.Table.Header, expected) {
+		t.Errorf("header: got %v, want %v", rows.Table.Header, expected)
+	}
+}
+
+func TestReadRowsNDJSON(t *testing.T) {
+	input := `{"city": "Provo", "state": "UT", "id": 7}` + "\n" +
+		"\n" +
+		"   \n" +
+		`{"zipcode": 84601}` + "\n" +
+		`{"zipCode": "84604", "extra": {"nested": true}}` + "\n"
+	rows, err := ReadRows(strings.NewReader(input), inputNDJSON, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*zipcode.Lookup{
+		{InputID: "7", City: "Provo", State: "UT"},
+		{ZIPCode: "84601"},
+		{ZIPCode: "84604"},
+	}
+	assertLookups(t, rows.Lookups, expected)
+	if expected := []string{"city", "id", "state", "zipcode", "extra"}; !reflect.DeepEqual(rows.Table.Header, expected) {
+		t.Errorf("header: got %v, want %v", rows.Table.Header, expected)
+	}
+	if extra := rows.Table.Get(rows.Table.Rows[2], "extra"); extra != `{"nested":true}` {
+		t.Errorf("extra: got %s", extra)
+	}
+}
+
+func TestReadRowsNDJSONRowNumberInputID(t *testing.T) {
+	input := `{"zipcode": "84601"}` + "\n\n" + `{"zipcode": "84604"}` + "\n"
+	rows, err := ReadRows(strings.NewReader(input), inputNDJSON, "input_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*zipcode.Lookup{{InputID: "1", ZIPCode: "84601"}, {InputID: "2", ZIPCode: "84604"}}
+	assertLookups(t, rows.Lookups, expected)
+}
+
+func TestReadRowsNDJSONMalformed(t *testing.T) {
+	input := `{"zipcode": "84601"}` + "\n" + `{"zipcode": ` + "\n"
+	_, err := ReadRows(strings.NewReader(input), inputNDJSON, "input_id")
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("expected an error naming line 2, got: %v", err)
+	}
+}
+
+func TestReadRowsUnrecognizedFormat(t *testing.T) {
+	if _, err := ReadRows(strings.NewReader(""), "xml", "input_id"); err == nil {
+		t.Error("expected an error for an unrecognized format")
+	}
+}
+
+func assertLookups(t *testing.T, actual, expected []*zipcode.Lookup) {
+	t.Helper()
+	if len(actual) != len(expected) {
+		t.Fatalf("got %d lookups, want %d", len(actual), len(expected))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(actual[i], expected[i]) {
+			t.Errorf("lookup %d: got %+v, want %+v", i, *actual[i], *expected[i])
+		}
+	}
+}
@@ -20,11 +20,24 @@ func main() {
 
 	inputs := NewInputs()
 	inputs.Flags()
-	client := wireup.BuildUSZIPCodeAPIClient(
+	options := []wireup.Option{
 		wireup.CustomBaseURL(inputs.baseURL),
 		wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
-		wireup.DebugHTTPOutput(),
-	)
+	}
+	if inputs.input == "" {
+		options = append(options, wireup.DebugHTTPOutput()) // with -input that would dump a request and response for every row of the file
+	}
+	client := wireup.BuildUSZIPCodeAPIClient(options...)
+
+	if inputs.input != "" {
+		rows := inputs.ReadRows()
+		if err := cli.SendZIPCodeLookups(client, rows.Lookups); err != nil {
+			log.Fatal(err)
+		}
+		inputs.WriteRows(rows)
+		return
+	}
+
 	batch := inputs.PopulateBatch()
 
 	if err := client.SendBatch(batch); err != nil {
@@ -49,6 +62,12 @@ type Inputs struct {
 	city    string
 	state   string
 	zipCode string
+	inputID string
+
+	input       string
+	inputFormat string
+	idColumn    string
+	output      string
 
 	lookup *zipcode.Lookup
 }
@@ -65,6 +84,11 @@ func (this *Inputs) Flags() {
 	flag.StringVar(&this.city, "city", "", "The City (US Street API, US ZIP Code API)")
 	flag.StringVar(&this.state, "state", "", "The State (US Street API, US ZIP Code API)")
 	flag.StringVar(&this.zipCode, "zipcode", "", "The ZIP Code (US Street API, US ZIP Code API)")
+	flag.StringVar(&this.inputID, "input_id", "", "The input_id field.")
+	flag.StringVar(&this.input, "input", "", "A file of city/state/zipcode records to look up in bulk ('-' for stdin): CSV with a header row, or NDJSON (one JSON object per line).")
+	flag.StringVar(&this.inputFormat, "input-format", "", "The -input format ('"+inputCSV+"' or '"+inputNDJSON+"'). Derived from the file extension when blank.")
+	flag.StringVar(&this.idColumn, "input-id-column", "input_id", "The -input column holding each record's input ID (the row number is used when the column is missing).")
+	flag.StringVar(&this.output, "output", "-", "Where to write the -input records joined to their results, as CSV ('-' for stdout).")
 	this.ParseFlags()
 }
 
@@ -111,9 +135,35 @@ func (this *Inputs) assembleLookupFromFlags() {
 	this.lookup.City = this.city
 	this.lookup.State = this.state
 	this.lookup.ZIPCode = this.zipCode
+	this.lookup.InputID = this.inputID
 }
 func (this *Inputs) assembleLookupFromQueryString(values url.Values) {
-	this.lookup.City = values.Get("city")
-	this.lookup.State = values.Get("state")
-	this.lookup.ZIPCode = values.Get("zipCode")
+	this.lookup = cli.NewZIPCodeLookup(values)
+}
+
+func (this *Inputs) ReadRows() *Rows {
+	reader, err := helps.OpenInput(this.input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer reader.Close()
+
+	rows, err := ReadRows(reader, InputFormat(this.inputFormat, this.input), this.idColumn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Read %d records from: %s", len(rows.Lookups), this.input)
+	return rows
+}
+
+func (this *Inputs) WriteRows(rows *Rows) {
+	writer, err := helps.CreateOutput(this.output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer writer.Close()
+
+	if err := rows.Write(writer); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-zipcode-api"
+)
+
+const (
+	auditValid       = "valid"
+	auditMismatch    = "zip_city_mismatch" // the city isn't one of the ZIP Code's cities
+	auditInvalidZIP  = "invalid_zipcode"   // the ZIP Code doesn't exist
+	auditAliasCity   = "alias_city"        // the city belongs to the ZIP Code but isn't its default (preferred) name
+	auditWrongState  = "wrong_state"       // the city belongs to the ZIP Code, but in another state
+	auditIncomplete  = "incomplete"        // the city, state or ZIP Code is missing
+	auditUnavailable = "unavailable"       // the API didn't return a result
+)
+
+// Audit checks one city/state/ZIP triple with two lookups: the ZIP Code alone (to learn which
+// cities it serves), and the city and state alone (to learn which ZIP Codes serve them).
+type Audit struct {
+	City    string
+	State   string
+	ZIPCode string
+
+	ByZIPCode   *zipcode.Lookup
+	ByCityState *zipcode.Lookup
+
+	Status string
+	Reason string
+
+	ProposedCity    string
+	ProposedState   string
+	ProposedZIPCode string
+}
+
+func NewAudit(city, state, zip string) *Audit {
+	city, state, zip = strings.TrimSpace(city), strings.TrimSpace(state), strings.TrimSpace(zip)
+	return &Audit{
+		City:        city,
+		State:       state,
+		ZIPCode:     zip,
+		ByZIPCode:   &zipcode.Lookup{ZIPCode: zip5(zip)},
+		ByCityState: &zipcode.Lookup{City: city, State: state},
+	}
+}
+
+// zip5 trims the +4 from a ZIP+4 (ie. '84101-1234' or '841011234'), which the API would report as invalid.
+func zip5(zip string) string {
+	digits := strings.Replace(zip, "-", "", 1)
+	if len(digits) != 9 || len(zip) > 10 || (len(zip) == 10 && zip[5] != '-') {
+		return zip
+	}
+	for _, character := range digits {
+		if character < '0' || character > '9' {
+			return zip
+		}
+	}
+	return zip[:5]
+}
+
+// Lookups are the lookups to send (skipping any that would be blank).
+func (this *Audit) Lookups() (lookups []*zipcode.Lookup) {
+	if this.ZIPCode != "" {
+		lookups = append(lookups, this.ByZIPCode)
+	}
+	if this.City != "" && this.State != "" {
+		lookups = append(lookups, this.ByCityState)
+	}
+	return lookups
+}
+
+// Classify compares the triple with the results, proposing a correction unless it's valid.
+func (this *Audit) Classify() {
+	this.ProposedCity, this.ProposedState, this.ProposedZIPCode = this.City, this.State, this.ZIPCode
+	byZIPCode, byCityState := valid(this.ByZIPCode.Result), valid(this.ByCityState.Result)
+
+	switch {
+	case this.City == "" || this.State == "" || this.ZIPCode == "":
+		this.classify(auditIncomplete, "the city, state or ZIP Code is blank")
+		if this.ZIPCode == "" {
+			this.proposeZIPCode(byCityState)
+		} else if byZIPCode != nil {
+			this.proposeDefaultCity(byZIPCode)
+		}
+	case this.ByZIPCode.Result == nil:
+		this.classify(auditUnavailable, "no result for the ZIP Code")
+	case byZIPCode == nil:
+		this.classify(auditInvalidZIP, reason(this.ByZIPCode.Result, "the ZIP Code doesn't exist"))
+		this.proposeZIPCode(byCityState)
+	default:
+		this.compare(byZIPCode, byCityState)
+	}
+}
+
+func (this *Audit) compare(byZIPCode, byCityState *zipcode.Result) {
+	var city *zipcode.CityState
+	for i, candidate := range byZIPCode.CityStates {
+		if same(candidate.City, this.City) {
+			city = &byZIPCode.CityStates[i]
+			break
+		}
+	}
+	switch {
+	case city == nil:
+		this.classify(auditMismatch, "the city isn't served by the ZIP Code")
+		if !this.proposeZIPCode(byCityState) {
+			this.proposeDefaultCity(byZIPCode)
+		}
+	case !same(city.StateAbbreviation, this.State) && !same(city.State, this.State):
+		this.classify(auditWrongState, "the ZIP Code and city are in "+city.StateAbbreviation)
+		this.ProposedState = city.StateAbbreviation
+	case !same(city.City, defaultCity(byZIPCode)):
+		reason := "an alternate name for " + defaultCity(byZIPCode)
+		if !city.MailableCity {
+			reason += " (not acceptable for mail)"
+		}
+		this.classify(auditAliasCity, reason)
+		this.proposeDefaultCity(byZIPCode)
+	default:
+		this.classify(auditValid, "")
+	}
+}
+
+func (this *Audit) classify(status, reason string) {
+	this.Status, this.Reason = status, reason
+}
+
+// proposeZIPCode suggests the city's ZIP Code, but only when it has just one.
+func (this *Audit) proposeZIPCode(byCityState *zipcode.Result) bool {
+	if byCityState == nil || len(byCityState.ZIPCodes) != 1 {
+		return false
+	}
+	this.ProposedZIPCode = byCityState.ZIPCodes[0].ZIPCode
+	return true
+}
+
+func (this *Audit) proposeDefaultCity(byZIPCode *zipcode.Result) {
+	if city := defaultCity(byZIPCode); city != "" {
+		this.ProposedCity = city
+	}
+	if len(byZIPCode.ZIPCodes) > 0 && byZIPCode.ZIPCodes[0].StateAbbreviation != "" {
+		this.ProposedState = byZIPCode.ZIPCodes[0].StateAbbreviation
+	}
+}
+
+// valid is the result, unless the API reported a problem (ie. an 'invalid_zipcode' status).
+func valid(result *zipcode.Result) *zipcode.Result {
+	if result == nil || result.Status != "" || len(result.CityStates) == 0 {
+		return nil
+	}
+	return result
+}
+
+func reason(result *zipcode.Result, fallback string) string {
+	if result.Reason != "" {
+		return result.Reason
+	}
+	if result.Status != "" {
+		return result.Status
+	}
+	return fallback
+}
+
+func defaultCity(result *zipcode.Result) string {
+	if len(result.ZIPCodes) > 0 && result.ZIPCodes[0].DefaultCity != "" {
+		return result.ZIPCodes[0].DefaultCity
+	}
+	return result.CityStates[0].City
+}
+
+func same(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
+
+///////////////////
+
+var auditColumns = []string{"audit", "audit_reason", "proposed_city", "proposed_state", "proposed_zipcode", "zipcode_cities", "city_zipcodes"}
+
+// Columns describes the audit (aligned with auditColumns). Nothing is proposed for valid (or unchecked) triples.
+func (this *Audit) Columns() []string {
+	columns := []string{this.Status, this.Reason, "", "", "", cities(this.ByZIPCode.Result), zipCodes(this.ByCityState.Result)}
+	if this.Status != auditValid && this.Status != auditUnavailable {
+		columns[2], columns[3], columns[4] = this.ProposedCity, this.ProposedState, this.ProposedZIPCode
+	}
+	return columns
+}
+
+func cities(result *zipcode.Result) string {
+	if valid(result) == nil {
+		return ""
+	}
+	var cities []string
+	for _, city := range result.CityStates {
+		cities = append(cities, city.City+", "+city.StateAbbreviation+" (mailable: "+strconv.FormatBool(city.MailableCity)+")")
+	}
+	return strings.Join(cities, ";")
+}
+
+func zipCodes(result *zipcode.Result) string {
+	if result == nil || result.Status != "" {
+		return ""
+	}
+	var zips []string
+	for _, zip := range result.ZIPCodes {
+		zips = append(zips, zip.ZIPCode)
+	}
+	return strings.Join(zips, ";")
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-zipcode-api"
+)
+
+var (
+	saltLakeCity = &zipcode.Result{
+		CityStates: []zipcode.CityState{
+			{City: "Salt Lake City", StateAbbreviation: "UT", State: "Utah", MailableCity: true},
+			{City: "SLC", StateAbbreviation: "UT", State: "Utah", MailableCity: true},
+			{City: "Salt Lake Cty", StateAbbreviation: "UT", State: "Utah", MailableCity: false},
+		},
+		ZIPCodes: []zipcode.ZIPCode{{ZIPCode: "84101", DefaultCity: "Salt Lake City", StateAbbreviation: "UT", State: "Utah"}},
+	}
+	provoZIPCode  = &zipcode.Result{ZIPCodes: []zipcode.ZIPCode{{ZIPCode: "84601"}}, CityStates: []zipcode.CityState{{City: "Provo", StateAbbreviation: "UT"}}}
+	sandyZIPCodes = &zipcode.Result{ZIPCodes: []zipcode.ZIPCode{{ZIPCode: "84070"}, {ZIPCode: "84092"}}, CityStates: []zipcode.CityState{{City: "Sandy", StateAbbreviation: "UT"}}}
+	invalidZIP    = &zipcode.Result{Status: "invalid_zipcode", Reason: "Invalid ZIP Code."}
+	invalidCity   = &zipcode.Result{Status: "invalid_city", Reason: "Invalid city."}
+)
+
+func TestAuditClassify(t *testing.T) {
+	for _, test := range []struct {
+		name                        string
+		city, state, zip            string
+		byZIPCode, byCityState      *zipcode.Result
+		status, reason              string
+		proposedCity, proposedState string
+		proposedZIPCode             string
+	}{
+		{
+			name: "valid", city: "salt lake city", state: "ut", zip: "84101",
+			byZIPCode: saltLakeCity, byCityState: saltLakeCity,
+			status: auditValid,
+		},
+		{
+			name: "valid with full state name", city: "Salt Lake City", state: "Utah", zip: "84101",
+			byZIPCode: saltLakeCity, byCityState: saltLakeCity,
+			status: auditValid,
+		},
+		{
+			name: "blank city proposes the default city", city: "", state: "UT", zip: "84101",
+			byZIPCode: saltLakeCity,
+			status:    auditIncomplete, reason: "the city, state or ZIP Code is blank",
+			proposedCity: "Salt Lake City", proposedState: "UT", proposedZIPCode: "84101",
+		},
+		{
+			name: "blank ZIP Code proposes the city's only ZIP Code", city: "Provo", state: "UT", zip: "",
+			byCityState: provoZIPCode,
+			status:      auditIncomplete, reason: "the city, state or ZIP Code is blank",
+			proposedCity: "Provo", proposedState: "UT", proposedZIPCode: "84601",
+		},
+		{
+			name: "nil result", city: "Salt Lake City", state: "UT", zip: "84101",
+			status: auditUnavailable, reason: "no result for the ZIP Code",
+			proposedCity: "Salt Lake City", proposedState: "UT", proposedZIPCode: "84101",
+		},
+		{
+			name: "invalid ZIP Code proposes the city's only ZIP Code", city: "Provo", state: "UT", zip: "99999",
+			byZIPCode: invalidZIP, byCityState: provoZIPCode,
+			status: auditInvalidZIP, reason: "Invalid ZIP Code.",
+			proposedCity: "Provo", proposedState: "UT", proposedZIPCode: "84601",
+		},
+		{
+			name: "invalid ZIP Code of a city with several ZIP Codes", city: "Sandy", state: "UT", zip: "99999",
+			byZIPCode: invalidZIP, byCityState: sandyZIPCodes,
+			status: auditInvalidZIP, reason: "Invalid ZIP Code.",
+			proposedCity: "Sandy", proposedState: "UT", proposedZIPCode: "99999",
+		},
+		{
+			name: "city missing from the ZIP Code proposes the city's only ZIP Code", city: "Provo", state: "UT", zip: "84101",
+			byZIPCode: saltLakeCity, byCityState: provoZIPCode,
+			status: auditMismatch, reason: "the city isn't served by the ZIP Code",
+			proposedCity: "Provo", proposedState: "UT", proposedZIPCode: "84601",
+		},
+		{
+			name: "city missing from the ZIP Code falls back to the default city", city: "Sandy", state: "UT", zip: "84101",
+			byZIPCode: saltLakeCity, byCityState: sandyZIPCodes,
+			status: auditMismatch, reason: "the city isn't served by the ZIP Code",
+			proposedCity: "Salt Lake City", proposedState: "UT", proposedZIPCode: "84101",
+		},
+		{
+			name: "unknown city falls back to the default city", city: "Nowhere", state: "UT", zip: "84101",
+			byZIPCode: saltLakeCity, byCityState: invalidCity,
+			status: auditMismatch, reason: "the city isn't served by the ZIP Code",
+			proposedCity: "Salt Lake City", proposedState: "UT", proposedZIPCode: "84101",
+		},
+		{
+			name: "wrong state abbreviation", city: "Salt Lake City", state: "ID", zip: "84101",
+			byZIPCode: saltLakeCity, byCityState: invalidCity,
+			status: auditWrongState, reason: "the ZIP Code and city are in UT",
+			proposedCity: "Salt Lake City", proposedState: "UT", proposedZIPCode: "84101",
+		},
+		{
+			name: "wrong state name", city: "Salt Lake City", state: "Idaho", zip: "84101",
+			byZIPCode: saltLakeCity, byCityState: invalidCity,
+			status: auditWrongState, reason: "the ZIP Code and city are in UT",
+			proposedCity: "Salt Lake City", proposedState: "UT", proposedZIPCode: "84101",
+		},
+		{
+			name: "mailable alias city", city: "SLC", state: "UT", zip: "84101",
+			byZIPCode: saltLakeCity, byCityState: invalidCity,
+			status: auditAliasCity, reason: "an alternate name for Salt Lake City",
+			proposedCity: "Salt Lake City", proposedState: "UT", proposedZIPCode: "84101",
+		},
+		{
+			name: "unmailable alias city", city: "Salt Lake Cty", state: "UT", zip: "84101",
+			byZIPCode: saltLakeCity, byCityState: invalidCity,
+			status: auditAliasCity, reason: "an alternate name for Salt Lake City (not acceptable for mail)",
+			proposedCity: "Salt Lake City", proposedState: "UT", proposedZIPCode: "84101",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			audit := NewAudit(test.city, test.state, test.zip)
+			audit.ByZIPCode.Result = test.byZIPCode
+			audit.ByCityState.Result = test.byCityState
+			audit.Classify()
+
+			if audit.Status != test.status || audit.Reason != test.reason {
+				t.Errorf("got %q (%q), want %q (%q)", audit.Status, audit.Reason, test.status, test.reason)
+			}
+			if test.status == auditValid {
+				return
+			}
+			if audit.ProposedCity != test.proposedCity || audit.ProposedState != test.proposedState || audit.ProposedZIPCode != test.proposedZIPCode {
+				t.Errorf("proposed %q, %q %q, want %q, %q %q",
+					audit.ProposedCity, audit.ProposedState, audit.ProposedZIPCode,
+					test.proposedCity, test.proposedState, test.proposedZIPCode)
+			}
+		})
+	}
+}
+
+func TestAuditLookups(t *testing.T) {
+	for _, test := range []struct {
+		city, state, zip string
+		lookups          int
+		lookupZIPCode    string
+	}{
+		{city: "Salt Lake City", state: "UT", zip: "84101", lookups: 2, lookupZIPCode: "84101"},
+		{city: "Salt Lake City", state: "UT", zip: "84101-1234", lookups: 2, lookupZIPCode: "84101"},
+		{city: "Salt Lake City", state: "UT", zip: "841011234", lookups: 2, lookupZIPCode: "84101"},
+		{city: "Salt Lake City", state: "UT", zip: "8410-11234", lookups: 2, lookupZIPCode: "8410-11234"},
+		{city: "Salt Lake City", state: "", zip: "84101", lookups: 1, lookupZIPCode: "84101"},
+		{city: "Salt Lake City", state: "UT", zip: " ", lookups: 1},
+	} {
+		audit := NewAudit(test.city, test.state, test.zip)
+		if lookups := audit.Lookups(); len(lookups) != test.lookups {
+			t.Errorf("%q %q %q: got %d lookups, want %d", test.city, test.state, test.zip, len(lookups), test.lookups)
+		}
+		if audit.ByZIPCode.ZIPCode != test.lookupZIPCode {
+			t.Errorf("%q: looked up ZIP Code %q, want %q", test.zip, audit.ByZIPCode.ZIPCode, test.lookupZIPCode)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-zipcode-api"
+	"github.com/smartystreets/smartystreets-go-sdk/wireup"
+
+	"github.com/mdwhatcott/smarty-cli"
+	"github.com/mdwhatcott/smarty-cli/helps"
+)
+
+func main() {
+	log.SetFlags(log.Lmicroseconds)
+
+	inputs := NewInputs()
+	client := wireup.BuildUSZIPCodeAPIClient(
+		wireup.CustomBaseURL(inputs.baseURL),
+		wireup.SecretKeyCredential(inputs.AuthID, inputs.AuthToken),
+	)
+	table := inputs.ReadTable()
+
+	var audits []*Audit
+	var lookups []*zipcode.Lookup
+	for _, row := range table.Rows {
+		audit := NewAudit(
+			table.Get(row, inputs.cityColumn),
+			table.Get(row, inputs.stateColumn),
+			table.Get(row, inputs.zipCodeColumn),
+		)
+		audits = append(audits, audit)
+		lookups = append(lookups, audit.Lookups()...)
+	}
+	if err := cli.SendZIPCodeLookups(client, lookups); err != nil {
+		log.Fatal(err)
+	}
+
+	results := &helps.Table{Header: append(append([]string{}, table.Header...), auditColumns...)}
+	statuses := make(map[string]int)
+	for i, audit := range audits {
+		audit.Classify()
+		statuses[audit.Status]++
+		if inputs.problemsOnly && audit.Status == auditValid {
+			continue
+		}
+		row := make([]string, len(table.Header))
+		copy(row, table.Rows[i])
+		results.Rows = append(results.Rows, append(row, audit.Columns()...))
+	}
+	log.Printf("Audited %d rows (%s).", len(audits), helps.SummarizeCounts(statuses))
+	inputs.WriteTable(results)
+}
+
+///////////////////
+
+type Inputs struct {
+	*cli.Inputs
+
+	baseURL string
+
+	input         string
+	output        string
+	cityColumn    string
+	stateColumn   string
+	zipCodeColumn string
+	problemsOnly  bool
+}
+
+func NewInputs() *Inputs {
+	this := &Inputs{Inputs: cli.NewInputs()}
+	this.flags()
+	return this
+}
+
+func (this *Inputs) flags() {
+	flag.StringVar(&this.baseURL, "baseURL", os.Getenv("SMARTY_US_ZIPCODE_API"), "The URL")
+	flag.StringVar(&this.input, "input", "-", "The CSV file of records to audit (with a header row). Defaults to stdin.")
+	flag.StringVar(&this.output, "output", "-", "Where to write the records joined to their audit results ('-' for stdout).")
+	flag.StringVar(&this.cityColumn, "city-column", "city", "The -input column holding the city.")
+	flag.StringVar(&this.stateColumn, "state-column", "state", "The -input column holding the state.")
+	flag.StringVar(&this.zipCodeColumn, "zipcode-column", "zipcode", "The -input column holding the ZIP Code (a ZIP+4 is audited by its first five digits).")
+	flag.BoolVar(&this.problemsOnly, "problems-only", false, "Leave valid records out of the -output.")
+	this.ParseFlags()
+}
+
+func (this *Inputs) ReadTable() *helps.Table {
+	table, err := helps.ReadTableFile(this.input, this.cityColumn, this.stateColumn, this.zipCodeColumn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return table
+}
+
+func (this *Inputs) WriteTable(table *helps.Table) {
+	if err := helps.WriteTableFile(this.output, table); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,245 @@
+package helps
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const earthRadiusMeters = 6371008.8
+
+// Distance is the great-circle (haversine) distance between two coordinates, in meters.
+func Distance(from, to Coordinate) float64 {
+	latitude1, latitude2 := radians(from.Latitude), radians(to.Latitude)
+	deltaLatitude := latitude2 - latitude1
+	deltaLongitude := radians(to.Longitude - from.Longitude)
+	a := math.Sin(deltaLatitude/2)*math.Sin(deltaLatitude/2) +
+		math.Cos(latitude1)*math.Cos(latitude2)*math.Sin(deltaLongitude/2)*math.Sin(deltaLongitude/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+///////////////////
+
+type BoundingBox struct {
+	South float64
+	West  float64
+	North float64
+	East  float64
+}
+
+// ParseBoundingBox accepts "south,west,north,east" (ie. "40.2,-111.7,40.3,-111.6").
+func ParseBoundingBox(raw string) (box BoundingBox, err error) {
+	fields := strings.Split(raw, ",")
+	if len(fields) != 4 {
+		return box, fmt.Errorf("invalid bounding box %q: should be south,west,north,east", raw)
+	}
+	if box.South, err = ParseLatitude(fields[0]); err != nil {
+		return box, err
+	}
+	if box.West, err = ParseLongitude(fields[1]); err != nil {
+		return box, err
+	}
+	if box.North, err = ParseLatitude(fields[2]); err != nil {
+		return box, err
+	}
+	if box.East, err = ParseLongitude(fields[3]); err != nil {
+		return box, err
+	}
+	if box.South > box.North || box.West > box.East {
+		return box, fmt.Errorf("invalid bounding box %q: south/west should precede north/east", raw)
+	}
+	return box, nil
+}
+
+// Area is a bounding box, optionally narrowed to the polygons within it.
+type Area struct {
+	Bounds   BoundingBox
+	Polygons [][][]Coordinate // each polygon is an outer ring followed by any holes
+}
+
+func NewBoxArea(box BoundingBox) *Area {
+	return &Area{Bounds: box}
+}
+
+// ReadGeoJSONArea reads the Polygon and MultiPolygon geometries from a GeoJSON
+// geometry, Feature or FeatureCollection.
+func ReadGeoJSONArea(content []byte) (*Area, error) {
+	var object geoJSONObject
+	if err := json.Unmarshal(content, &object); err != nil {
+		return nil, err
+	}
+	area := &Area{}
+	if err := area.add(object); err != nil {
+		return nil, err
+	}
+	if len(area.Polygons) == 0 {
+		return nil, errors.New("the GeoJSON has no Polygon or MultiPolygon geometries")
+	}
+	area.Bounds = area.bounds()
+	return area, nil
+}
+
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSONObject  `json:"geometry"`
+	Features    []geoJSONObject `json:"features"`
+	Geometries  []geoJSONObject `json:"geometries"`
+}
+
+func (this *Area) add(object geoJSONObject) error {
+	switch object.Type {
+	case "FeatureCollection":
+		for _, feature := range object.Features {
+			if err := this.add(feature); err != nil {
+				return err
+			}
+		}
+	case "GeometryCollection":
+		for _, geometry := range object.Geometries {
+			if err := this.add(geometry); err != nil {
+				return err
+			}
+		}
+	case "Feature":
+		if object.Geometry != nil {
+			return this.add(*object.Geometry)
+		}
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(object.Coordinates, &polygon); err != nil {
+			return err
+		}
+		this.Polygons = append(this.Polygons, rings(polygon))
+	case "MultiPolygon":
+		var polygons [][][][]float64
+		if err := json.Unmarshal(object.Coordinates, &polygons); err != nil {
+			return err
+		}
+		for _, polygon := range polygons {
+			this.Polygons = append(this.Polygons, rings(polygon))
+		}
+	}
+	return nil
+}
+
+// rings converts GeoJSON positions, which are ordered longitude first.
+func rings(polygon [][][]float64) (converted [][]Coordinate) {
+	for _, ring := range polygon {
+		var points []Coordinate
+		for _, position := range ring {
+			if len(position) >= 2 {
+				points = append(points, Coordinate{Latitude: position[1], Longitude: position[0]})
+			}
+		}
+		converted = append(converted, points)
+	}
+	return converted
+}
+
+func (this *Area) bounds() BoundingBox {
+	box := BoundingBox{South: 90, West: 180, North: -90, East: -180}
+	for _, polygon := range this.Polygons {
+		if len(polygon) == 0 {
+			continue
+		}
+		for _, point := range polygon[0] {
+			box.South = math.Min(box.South, point.Latitude)
+			box.North = math.Max(box.North, point.Latitude)
+			box.West = math.Min(box.West, point.Longitude)
+			box.East = math.Max(box.East, point.Longitude)
+		}
+	}
+	return box
+}
+
+// Contains reports whether the point is inside the bounds and, when there are polygons,
+// inside the outer ring (but not a hole) of at least one of them.
+func (this *Area) Contains(point Coordinate) bool {
+	box := this.Bounds
+	if point.Latitude < box.South || point.Latitude > box.North || point.Longitude < box.West || point.Longitude > box.East {
+		return false
+	}
+	if len(this.Polygons) == 0 {
+		return true
+	}
+	for _, polygon := range this.Polygons {
+		if len(polygon) == 0 || !insideRing(point, polygon[0]) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if insideRing(point, hole) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// insideRing is the even-odd (ray casting) test, treating degrees as planar.
+func insideRing(point Coordinate, ring []Coordinate) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Latitude > point.Latitude) != (b.Latitude > point.Latitude) &&
+			point.Longitude < (b.Longitude-a.Longitude)*(point.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+///////////////////
+
+var ErrTooManyPoints = errors.New("too many grid points")
+
+// maxGridCandidates bounds how many points Grid considers (inside the area or not), so that
+// a tiny spacing fails quickly, even without a limit or when few points fall inside a polygon.
+const maxGridCandidates = 10000000
+
+// Grid lays out points about spacing meters apart (north/south and east/west) across the area,
+// starting from its southwest corner. It stops with ErrTooManyPoints once it exceeds limit points
+// (when positive), or when the spacing is so fine that there would be too many points to consider.
+func Grid(area *Area, spacing float64, limit int) (points []Coordinate, err error) {
+	if spacing <= 0 || math.IsNaN(spacing) || math.IsInf(spacing, 0) {
+		return nil, fmt.Errorf("invalid grid spacing: %v", spacing)
+	}
+	box := area.Bounds
+	step := spacing / earthRadiusMeters * 180 / math.Pi
+	rows := (box.North - box.South) / step
+	if rows >= maxGridCandidates {
+		return nil, ErrTooManyPoints
+	}
+	candidates := 0
+	for row := 0; row <= int(rows); row++ {
+		latitude := box.South + float64(row)*step
+		longitudeStep := step / math.Max(math.Cos(radians(latitude)), 1e-6)
+		columns := (box.East - box.West) / longitudeStep
+		if float64(candidates)+columns >= maxGridCandidates {
+			return points, ErrTooManyPoints
+		}
+		candidates += int(columns) + 1
+		for column := 0; column <= int(columns); column++ {
+			point := Coordinate{Latitude: latitude, Longitude: box.West + float64(column)*longitudeStep}
+			if !area.Contains(point) {
+				continue
+			}
+			if limit > 0 && len(points) == limit {
+				return points, ErrTooManyPoints
+			}
+			points = append(points, point)
+		}
+	}
+	return points, nil
+}
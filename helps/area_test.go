@@ -0,0 +1,182 @@
+package helps
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	for _, test := range []struct {
+		from, to Coordinate
+		expected float64
+	}{
+		{from: Coordinate{0, 0}, to: Coordinate{0, 0}, expected: 0},
+		{from: Coordinate{0, 0}, to: Coordinate{1, 0}, expected: 111195},
+		{from: Coordinate{0, 0}, to: Coordinate{0, 1}, expected: 111195},
+		{from: Coordinate{60, 0}, to: Coordinate{60, 1}, expected: 55597},
+		{from: Coordinate{90, 0}, to: Coordinate{-90, 0}, expected: math.Pi * earthRadiusMeters},
+	} {
+		if actual := Distance(test.from, test.to); math.Abs(actual-test.expected) > 1 {
+			t.Errorf("%v -> %v: got %f, want %f", test.from, test.to, actual, test.expected)
+		}
+	}
+}
+
+func TestParseBoundingBox(t *testing.T) {
+	box, err := ParseBoundingBox("40.2,-111.7,40.3,-111.6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (BoundingBox{South: 40.2, West: -111.7, North: 40.3, East: -111.6}); box != expected {
+		t.Errorf("got %+v, want %+v", box, expected)
+	}
+}
+
+func TestParseBoundingBoxErrors(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"40.2,-111.7,40.3",
+		"40.2,-111.7,40.3,-111.6,1",
+		"40.3,-111.7,40.2,-111.6", // south above north
+		"40.2,-111.6,40.3,-111.7", // west east of east
+		"91,-111.7,92,-111.6",
+		"40.2,-181,40.3,-111.6",
+		"40.2,abc,40.3,-111.6",
+	} {
+		if box, err := ParseBoundingBox(raw); err == nil {
+			t.Errorf("%q: expected an error, got %+v", raw, box)
+		}
+	}
+}
+
+// concave is a U shape over (0,0)-(3,3) with a notch from (1,1) up to (2,3).
+const concave = `{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [
+	[[0,0], [3,0], [3,3], [2,3], [2,1], [1,1], [1,3], [0,3], [0,0]]
+]}}`
+
+// holed is a square over (10,10)-(13,13) with a hole over (11,11)-(12,12), and a second square beside it.
+const holed = `{"type": "FeatureCollection", "features": [{"type": "Feature", "geometry": {"type": "MultiPolygon", "coordinates": [
+	[[[10,10], [13,10], [13,13], [10,13], [10,10]], [[11,11], [12,11], [12,12], [11,12], [11,11]]],
+	[[[14,10], [15,10], [15,11], [14,11], [14,10]]]
+]}}]}`
+
+func TestAreaContains(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		geoJSON  string
+		point    Coordinate
+		expected bool
+	}{
+		{name: "concave: left arm", geoJSON: concave, point: Coordinate{Latitude: 2, Longitude: 0.5}, expected: true},
+		{name: "concave: right arm", geoJSON: concave, point: Coordinate{Latitude: 2, Longitude: 2.5}, expected: true},
+		{name: "concave: base", geoJSON: concave, point: Coordinate{Latitude: 0.5, Longitude: 1.5}, expected: true},
+		{name: "concave: notch", geoJSON: concave, point: Coordinate{Latitude: 2, Longitude: 1.5}, expected: false},
+		{name: "concave: outside bounds", geoJSON: concave, point: Coordinate{Latitude: 4, Longitude: 1.5}, expected: false},
+		{name: "holed: ring", geoJSON: holed, point: Coordinate{Latitude: 10.5, Longitude: 10.5}, expected: true},
+		{name: "holed: hole", geoJSON: holed, point: Coordinate{Latitude: 11.5, Longitude: 11.5}, expected: false},
+		{name: "holed: second polygon", geoJSON: holed, point: Coordinate{Latitude: 10.5, Longitude: 14.5}, expected: true},
+		{name: "holed: between polygons", geoJSON: holed, point: Coordinate{Latitude: 10.5, Longitude: 13.5}, expected: false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			area, err := ReadGeoJSONArea([]byte(test.geoJSON))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := area.Contains(test.point); actual != test.expected {
+				t.Errorf("got %t, want %t", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestReadGeoJSONArea(t *testing.T) {
+	area, err := ReadGeoJSONArea([]byte(holed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(area.Polygons) != 2 || len(area.Polygons[0]) != 2 {
+		t.Errorf("got %d polygons, want 2 (the first with a hole)", len(area.Polygons))
+	}
+	if expected := (BoundingBox{South: 10, West: 10, North: 13, East: 15}); area.Bounds != expected {
+		t.Errorf("bounds: got %+v, want %+v", area.Bounds, expected)
+	}
+	if _, err := ReadGeoJSONArea([]byte(`{"type": "Point", "coordinates": [1, 2]}`)); err == nil {
+		t.Error("expected an error for GeoJSON without polygons")
+	}
+}
+
+func TestGridBoxPointCount(t *testing.T) {
+	area := NewBoxArea(BoundingBox{South: 0, West: 0, North: 0.01, East: 0.01}) // about 1112 meters square
+	for _, test := range []struct {
+		spacing  float64
+		expected int
+	}{
+		{spacing: 2000, expected: 1},
+		{spacing: 1000, expected: 4},
+		{spacing: 500, expected: 9},
+		{spacing: 100, expected: 144},
+	} {
+		points, err := Grid(area, test.spacing, 0)
+		if err != nil {
+			t.Errorf("spacing %v: unexpected error: %v", test.spacing, err)
+		}
+		if len(points) != test.expected {
+			t.Errorf("spacing %v: got %d points, want %d", test.spacing, len(points), test.expected)
+		}
+		for _, point := range points {
+			if !area.Contains(point) {
+				t.Errorf("spacing %v: %v is outside the box", test.spacing, point)
+			}
+		}
+	}
+}
+
+func TestGridConcavePolygon(t *testing.T) {
+	area, err := ReadGeoJSONArea([]byte(concave))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spacing := 0.5 * math.Pi / 180 * earthRadiusMeters // half a degree of latitude
+	points, err := Grid(area, spacing, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, point := range points {
+		if point.Latitude > 1 && point.Longitude > 1 && point.Longitude < 2 {
+			t.Errorf("%v is in the notch", point)
+		}
+	}
+	if len(points) == 0 {
+		t.Error("expected points in the polygon")
+	}
+}
+
+func TestGridLimit(t *testing.T) {
+	area := NewBoxArea(BoundingBox{South: 0, West: 0, North: 0.01, East: 0.01})
+
+	points, err := Grid(area, 500, 9)
+	if err != nil || len(points) != 9 {
+		t.Errorf("at the limit: got %d points (err: %v), want 9", len(points), err)
+	}
+
+	points, err = Grid(area, 500, 4)
+	if err != ErrTooManyPoints || len(points) != 4 {
+		t.Errorf("over the limit: got %d points (err: %v), want 4 and ErrTooManyPoints", len(points), err)
+	}
+}
+
+func TestGridTooFine(t *testing.T) {
+	area := NewBoxArea(BoundingBox{South: -80, West: -180, North: 80, East: 180})
+	if _, err := Grid(area, 0.01, 0); err != ErrTooManyPoints {
+		t.Errorf("got %v, want ErrTooManyPoints", err)
+	}
+}
+
+func TestGridInvalidSpacing(t *testing.T) {
+	area := NewBoxArea(BoundingBox{South: 0, West: 0, North: 1, East: 1})
+	for _, spacing := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		if _, err := Grid(area, spacing, 0); err == nil || err == ErrTooManyPoints {
+			t.Errorf("spacing %v: got %v, want an invalid spacing error", spacing, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package helps
+
+import "math"
+
+const (
+	FeetPerMeter  = 3.280839895
+	MetersPerMile = 1609.344
+)
+
+// Bearing is the initial great-circle bearing from one coordinate to another,
+// in degrees clockwise from true north (0 up to, but not including, 360).
+func Bearing(from, to Coordinate) float64 {
+	latitude1, latitude2 := radians(from.Latitude), radians(to.Latitude)
+	deltaLongitude := radians(to.Longitude - from.Longitude)
+	y := math.Sin(deltaLongitude) * math.Cos(latitude2)
+	x := math.Cos(latitude1)*math.Sin(latitude2) - math.Sin(latitude1)*math.Cos(latitude2)*math.Cos(deltaLongitude)
+	degrees := math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+	if degrees >= 360 {
+		degrees = 0
+	}
+	return degrees
+}
+
+var compassPoints = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// CompassPoint names the nearest of the 16 compass points to the bearing (ie. 100 is "E").
+func CompassPoint(bearing float64) string {
+	sector := int(math.Floor(math.Mod(bearing, 360)/22.5+0.5)) % len(compassPoints)
+	if sector < 0 {
+		sector += len(compassPoints)
+	}
+	return compassPoints[sector]
+}
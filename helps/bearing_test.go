@@ -0,0 +1,56 @@
+package helps
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBearing(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		to       Coordinate
+		expected float64
+	}{
+		{name: "north", to: Coordinate{1, 0}, expected: 0},
+		{name: "east", to: Coordinate{0, 1}, expected: 90},
+		{name: "south", to: Coordinate{-1, 0}, expected: 180},
+		{name: "west", to: Coordinate{0, -1}, expected: 270},
+		{name: "northeast", to: Coordinate{1, 1}, expected: 44.9956},
+		{name: "just west of north", to: Coordinate{1, -0.0001}, expected: 359.9943},
+		{name: "same point", to: Coordinate{0, 0}, expected: 0},
+	} {
+		actual := Bearing(Coordinate{0, 0}, test.to)
+		if math.Abs(actual-test.expected) > 1e-4 {
+			t.Errorf("%s: got %f, want %f", test.name, actual, test.expected)
+		}
+		if actual < 0 || actual >= 360 {
+			t.Errorf("%s: %f is out of range", test.name, actual)
+		}
+	}
+}
+
+func TestCompassPoint(t *testing.T) {
+	for _, test := range []struct {
+		bearing  float64
+		expected string
+	}{
+		{bearing: 0, expected: "N"},
+		{bearing: 90, expected: "E"},
+		{bearing: 180, expected: "S"},
+		{bearing: 270, expected: "W"},
+		{bearing: 45, expected: "NE"},
+		{bearing: 100, expected: "E"},
+		{bearing: 11.24, expected: "N"},
+		{bearing: 11.25, expected: "NNE"},
+		{bearing: 348.74, expected: "NNW"},
+		{bearing: 348.75, expected: "N"},
+		{bearing: 359.99, expected: "N"},
+		{bearing: 360, expected: "N"},
+		{bearing: 450, expected: "E"},
+		{bearing: -90, expected: "W"},
+	} {
+		if actual := CompassPoint(test.bearing); actual != test.expected {
+			t.Errorf("%v: got %s, want %s", test.bearing, actual, test.expected)
+		}
+	}
+}
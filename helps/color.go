@@ -0,0 +1,27 @@
+package helps
+
+import "os"
+
+const (
+	ColorReset  = "\033[0m"
+	ColorRed    = "\033[31m"
+	ColorGreen  = "\033[32m"
+	ColorYellow = "\033[33m"
+	ColorBlue   = "\033[34m"
+	ColorGray   = "\033[90m"
+)
+
+func Colorize(text, color string, enabled bool) string {
+	if !enabled || color == "" || text == "" {
+		return text
+	}
+	return color + text + ColorReset
+}
+
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,225 @@
+package helps
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type Coordinate struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func (this Coordinate) String() string {
+	return strconv.FormatFloat(this.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(this.Longitude, 'f', -1, 64)
+}
+
+// ParseCoordinate accepts a latitude/longitude pair in any of these forms:
+//
+//	40.25,-111.67                 (decimal degrees, separated by a comma or spaces)
+//	40°15'N 111°40'12"W           (degrees, minutes and seconds with hemispheres, in either order)
+//	geo:40.25,-111.67;u=10        (a geo URI, RFC 5870)
+//	9x0qrv                        (a geohash, decoded to the center of its cell)
+//	geohash:9wen                  (a geohash that would otherwise read as hemispheres, like 40N111W)
+func ParseCoordinate(raw string) (Coordinate, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return Coordinate{}, fmt.Errorf("no coordinate provided")
+	}
+	if strings.HasPrefix(strings.ToLower(value), "geo:") {
+		return parseGeoURI(value)
+	}
+	if strings.HasPrefix(strings.ToLower(value), "geohash:") {
+		return DecodeGeohash(strings.TrimSpace(value[len("geohash:"):]))
+	}
+	if isGeohash(value) {
+		return DecodeGeohash(value)
+	}
+
+	first, second, err := splitPair(value)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	if hemisphere(first) == 'E' || hemisphere(first) == 'W' || hemisphere(second) == 'N' || hemisphere(second) == 'S' {
+		first, second = second, first
+	}
+	latitude, err := ParseLatitude(first)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	longitude, err := ParseLongitude(second)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	return Coordinate{Latitude: latitude, Longitude: longitude}, nil
+}
+
+// splitPair separates the two halves at a comma, at the hemisphere letters, or between two plain numbers.
+func splitPair(value string) (first, second string, err error) {
+	if parts := strings.Split(value, ","); len(parts) == 2 {
+		return parts[0], parts[1], nil
+	}
+	runes := []rune(value)
+	leading := strings.ContainsRune("NSEWnsew", runes[0]) // ie. N40°15' W111°40'
+	for i := 1; i < len(runes)-1; i++ {
+		if !strings.ContainsRune("NSEWnsew", runes[i]) {
+			continue
+		}
+		if leading {
+			return string(runes[:i]), string(runes[i:]), nil
+		}
+		return string(runes[:i+1]), string(runes[i+1:]), nil
+	}
+	if fields := strings.Fields(value); len(fields) == 2 {
+		return fields[0], fields[1], nil
+	}
+	return "", "", fmt.Errorf("could not find a latitude and longitude in: %q", value)
+}
+
+func ParseLatitude(raw string) (float64, error) {
+	return parseDegrees(raw, "latitude", 90, 'N', 'S')
+}
+
+func ParseLongitude(raw string) (float64, error) {
+	return parseDegrees(raw, "longitude", 180, 'E', 'W')
+}
+
+// parseDegrees accepts decimal degrees (-111.67), or degrees with optional minutes and seconds
+// (111°40'12"W, 111 40 12 W, W111°40.2') where the hemisphere may replace the sign.
+func parseDegrees(raw, name string, limit float64, positive, negative rune) (float64, error) {
+	value := strings.ToUpper(strings.TrimSpace(raw))
+	if value == "" {
+		return 0, fmt.Errorf("no %s provided", name)
+	}
+	sign := 1.0
+	if found := hemisphere(value); found != 0 {
+		if found != positive && found != negative {
+			return 0, fmt.Errorf("invalid %s %q: hemisphere should be %c or %c", name, raw, positive, negative)
+		}
+		if found == negative {
+			sign = -1
+		}
+		value = strings.Trim(value, "NSEW ")
+	}
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`°º˚'’′"”″`, r)
+	})
+	if len(fields) == 0 || len(fields) > 3 {
+		return 0, fmt.Errorf("invalid %s: %q", name, raw)
+	}
+
+	var degrees float64
+	for i, field := range fields {
+		number, err := strconv.ParseFloat(field, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return 0, fmt.Errorf("invalid %s: %q", name, raw)
+		}
+		if i == 0 {
+			if number < 0 {
+				sign, number = -sign, -number
+			}
+			degrees = number
+			continue
+		}
+		if number < 0 || number >= 60 {
+			return 0, fmt.Errorf("invalid %s %q: minutes and seconds should be between 0 and 60", name, raw)
+		}
+		degrees += number / math.Pow(60, float64(i))
+	}
+	degrees *= sign
+	if degrees < -limit || degrees > limit {
+		return 0, fmt.Errorf("invalid %s %q: should be between -%v and %v", name, raw, limit, limit)
+	}
+	return degrees, nil
+}
+
+// hemisphere finds a leading or trailing N, S, E or W (returning 0 if there isn't one).
+func hemisphere(value string) rune {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return 0
+	}
+	for _, r := range []rune{[]rune(value)[0], []rune(value)[len([]rune(value))-1]} {
+		if strings.ContainsRune("NSEW", r) {
+			return r
+		}
+	}
+	return 0
+}
+
+func parseGeoURI(value string) (Coordinate, error) {
+	body := value[len("geo:"):]
+	if parameters := strings.Index(body, ";"); parameters >= 0 {
+		body = body[:parameters]
+	}
+	parts := strings.Split(body, ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Coordinate{}, fmt.Errorf("invalid geo URI: %q", value)
+	}
+	latitude, err := ParseLatitude(parts[0])
+	if err != nil {
+		return Coordinate{}, err
+	}
+	longitude, err := ParseLongitude(parts[1])
+	if err != nil {
+		return Coordinate{}, err
+	}
+	return Coordinate{Latitude: latitude, Longitude: longitude}, nil
+}
+
+///////////////////
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// isGeohash requires at least one letter other than a hemisphere (n, s, e, w)
+// so that neither a lone number nor a value like 40N111W is mistaken for a geohash.
+func isGeohash(value string) bool {
+	if len(value) > 12 {
+		return false
+	}
+	letters := 0
+	for _, r := range strings.ToLower(value) {
+		if !strings.ContainsRune(geohashAlphabet, r) {
+			return false
+		}
+		if unicode.IsLetter(r) && !strings.ContainsRune("nsew", r) {
+			letters++
+		}
+	}
+	return letters > 0
+}
+
+func DecodeGeohash(hash string) (Coordinate, error) {
+	if hash == "" {
+		return Coordinate{}, fmt.Errorf("no geohash provided")
+	}
+	latitude := [2]float64{-90, 90}
+	longitude := [2]float64{-180, 180}
+	even := true
+	for _, r := range strings.ToLower(hash) {
+		index := strings.IndexRune(geohashAlphabet, r)
+		if index < 0 {
+			return Coordinate{}, fmt.Errorf("invalid geohash: %q", hash)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			interval := &latitude
+			if even {
+				interval = &longitude
+			}
+			middle := (interval[0] + interval[1]) / 2
+			if index&(1<<uint(bit)) != 0 {
+				interval[0] = middle
+			} else {
+				interval[1] = middle
+			}
+			even = !even
+		}
+	}
+	return Coordinate{
+		Latitude:  (latitude[0] + latitude[1]) / 2,
+		Longitude: (longitude[0] + longitude[1]) / 2,
+	}, nil
+}
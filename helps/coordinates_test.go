@@ -0,0 +1,127 @@
+package helps
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseCoordinate(t *testing.T) {
+	for _, test := range []struct {
+		raw       string
+		expected  Coordinate
+		tolerance float64
+	}{
+		{raw: "40.25,-111.67", expected: Coordinate{40.25, -111.67}},
+		{raw: " 40.25 , -111.67 ", expected: Coordinate{40.25, -111.67}},
+		{raw: "40.25 -111.67", expected: Coordinate{40.25, -111.67}},
+		{raw: "0,0", expected: Coordinate{0, 0}},
+		{raw: "0 0", expected: Coordinate{0, 0}},
+		{raw: "-90,180", expected: Coordinate{-90, 180}},
+		{raw: `40°15'N 111°40'12"W`, expected: Coordinate{40.25, -111.67}},
+		{raw: `111°40'12"W 40°15'N`, expected: Coordinate{40.25, -111.67}},
+		{raw: `N40°15' W111°40'12"`, expected: Coordinate{40.25, -111.67}},
+		{raw: "40 15 S, 111 40 12 E", expected: Coordinate{-40.25, 111.67}},
+		{raw: "40°15.0'N,111°40.2'W", expected: Coordinate{40.25, -111.67}},
+		{raw: "geo:40.25,-111.67", expected: Coordinate{40.25, -111.67}},
+		{raw: "GEO:40.25,-111.67,1500;u=10", expected: Coordinate{40.25, -111.67}},
+		{raw: "geo:0,0", expected: Coordinate{0, 0}},
+		{raw: "ezs42", expected: Coordinate{42.605, -5.603}, tolerance: 0.03},
+		{raw: "EZS42", expected: Coordinate{42.605, -5.603}, tolerance: 0.03},
+		{raw: "40N111W", expected: Coordinate{40, -111}},
+		{raw: "40n111w", expected: Coordinate{40, -111}},
+		{raw: "40.1N 111.2W", expected: Coordinate{40.1, -111.2}},
+		{raw: "geohash:ezs42", expected: Coordinate{42.605, -5.603}, tolerance: 0.03},
+		{raw: "geohash:40n", expected: Coordinate{-89.296875, -80.859375}},
+	} {
+		actual, err := ParseCoordinate(test.raw)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.raw, err)
+			continue
+		}
+		tolerance := test.tolerance
+		if tolerance == 0 {
+			tolerance = 1e-9
+		}
+		if math.Abs(actual.Latitude-test.expected.Latitude) > tolerance ||
+			math.Abs(actual.Longitude-test.expected.Longitude) > tolerance {
+			t.Errorf("%q: got %v, want %v", test.raw, actual, test.expected)
+		}
+	}
+}
+
+func TestParseCoordinateErrors(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"   ",
+		"40.25",
+		"40.1N",
+		"40n",
+		"geohash:",
+		"geohash:ezs4a",
+		"1,2,3",
+		"91,0",
+		"-90.5,0",
+		"0,181",
+		"0,-180.0001",
+		"40°75'N 111°W",
+		`40°15'60"N 111°W`,
+		"40 E, 111 E",
+		"40.25,abc",
+		"NaN,0",
+		"0,Inf",
+		"geo:40.25",
+		"geo:91,0",
+		"geo:1,2,3,4",
+		"hello world",
+	} {
+		if actual, err := ParseCoordinate(raw); err == nil {
+			t.Errorf("%q: expected an error, got %v", raw, actual)
+		}
+	}
+}
+
+func TestParseLatitudeAndLongitude(t *testing.T) {
+	for _, test := range []struct {
+		parse    func(string) (float64, error)
+		raw      string
+		expected float64
+		invalid  bool
+	}{
+		{parse: ParseLatitude, raw: "0", expected: 0},
+		{parse: ParseLatitude, raw: "-0", expected: 0},
+		{parse: ParseLatitude, raw: "90", expected: 90},
+		{parse: ParseLatitude, raw: "40.25", expected: 40.25},
+		{parse: ParseLatitude, raw: "40 15 S", expected: -40.25},
+		{parse: ParseLatitude, raw: "-40°15'", expected: -40.25},
+		{parse: ParseLatitude, raw: "90.1", invalid: true},
+		{parse: ParseLatitude, raw: "40 W", invalid: true},
+		{parse: ParseLatitude, raw: "", invalid: true},
+		{parse: ParseLatitude, raw: "40 -15", invalid: true},
+		{parse: ParseLatitude, raw: "1 2 3 4", invalid: true},
+		{parse: ParseLongitude, raw: "0", expected: 0},
+		{parse: ParseLongitude, raw: "-180", expected: -180},
+		{parse: ParseLongitude, raw: "111°40'12\"W", expected: -111.67},
+		{parse: ParseLongitude, raw: "W111 40.2", expected: -111.67},
+		{parse: ParseLongitude, raw: "180.0001", invalid: true},
+		{parse: ParseLongitude, raw: "111 N", invalid: true},
+	} {
+		actual, err := test.parse(test.raw)
+		if test.invalid {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %v", test.raw, actual)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.raw, err)
+		} else if math.Abs(actual-test.expected) > 1e-9 {
+			t.Errorf("%q: got %v, want %v", test.raw, actual, test.expected)
+		}
+	}
+}
+
+func TestDecodeGeohashInvalid(t *testing.T) {
+	if _, err := DecodeGeohash("ezs4a"); err == nil {
+		t.Error("expected an error for a character outside the geohash alphabet")
+	}
+}
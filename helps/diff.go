@@ -0,0 +1,110 @@
+package helps
+
+import (
+	"fmt"
+	"strings"
+)
+
+type FieldChange string
+
+const (
+	FieldUnchanged FieldChange = "unchanged"
+	FieldAdded     FieldChange = "added"
+	FieldCorrected FieldChange = "corrected"
+	FieldRemoved   FieldChange = "removed"
+)
+
+// FieldDiff lines up a single input field against its standardized counterpart.
+type FieldDiff struct {
+	Field  string
+	Input  string
+	Output string
+}
+
+func (this FieldDiff) Change() FieldChange {
+	input := normalizeField(this.Input)
+	output := normalizeField(this.Output)
+	switch {
+	case input == output:
+		return FieldUnchanged
+	case input == "":
+		return FieldAdded
+	case output == "":
+		return FieldRemoved
+	default:
+		return FieldCorrected
+	}
+}
+
+func normalizeField(value string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(value), " "))
+}
+
+// DumpDiff renders the provided fields as an aligned table, one field per line.
+// Fields that are blank on both sides are omitted, and nothing at all is rendered
+// when no field changed. When colored is false the change is conveyed by the
+// marker in the first column (+ ~ -) and a suffix.
+func DumpDiff(fields []FieldDiff, colored bool) string {
+	if !changed(fields) {
+		return ""
+	}
+	fieldWidth, inputWidth := 0, 0
+	for _, field := range fields {
+		fieldWidth = maxInt(fieldWidth, len(field.Field))
+		inputWidth = maxInt(inputWidth, len([]rune(field.Input)))
+	}
+
+	builder := new(strings.Builder)
+	for _, field := range fields {
+		if field.Input == "" && field.Output == "" {
+			continue
+		}
+		change := field.Change()
+		line := fmt.Sprintf("%s %-*s  %s  ->  %s",
+			diffMarkers[change], fieldWidth, field.Field, padRight(field.Input, inputWidth), field.Output)
+		line = strings.TrimRight(line, " ")
+		if colored {
+			line = Colorize(line, diffColors[change], true)
+		} else if change != FieldUnchanged {
+			line += "  (" + string(change) + ")"
+		}
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func changed(fields []FieldDiff) bool {
+	for _, field := range fields {
+		if field.Change() != FieldUnchanged {
+			return true
+		}
+	}
+	return false
+}
+
+func padRight(value string, width int) string {
+	return value + strings.Repeat(" ", maxInt(0, width-len([]rune(value))))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+var (
+	diffMarkers = map[FieldChange]string{
+		FieldUnchanged: " ",
+		FieldAdded:     "+",
+		FieldCorrected: "~",
+		FieldRemoved:   "-",
+	}
+	diffColors = map[FieldChange]string{
+		FieldUnchanged: "",
+		FieldAdded:     ColorGreen,
+		FieldCorrected: ColorYellow,
+		FieldRemoved:   ColorRed,
+	}
+)
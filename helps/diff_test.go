@@ -0,0 +1,76 @@
+package helps
+
+import "testing"
+
+func TestFieldDiffChange(t *testing.T) {
+	for _, test := range []struct {
+		diff     FieldDiff
+		expected FieldChange
+	}{
+		{diff: FieldDiff{Input: "", Output: ""}, expected: FieldUnchanged},
+		{diff: FieldDiff{Input: "provo", Output: "PROVO"}, expected: FieldUnchanged},
+		{diff: FieldDiff{Input: " 1  Main St ", Output: "1 Main St"}, expected: FieldUnchanged},
+		{diff: FieldDiff{Input: "", Output: "1234"}, expected: FieldAdded},
+		{diff: FieldDiff{Input: "Apt 5", Output: ""}, expected: FieldRemoved},
+		{diff: FieldDiff{Input: "1 Main", Output: "1 Main St"}, expected: FieldCorrected},
+	} {
+		if actual := test.diff.Change(); actual != test.expected {
+			t.Errorf("%q -> %q: got %s, want %s", test.diff.Input, test.diff.Output, actual, test.expected)
+		}
+	}
+}
+
+func TestDumpDiff(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		fields   []FieldDiff
+		colored  bool
+		expected string
+	}{
+		{
+			name:     "nothing",
+			expected: "",
+		},
+		{
+			name: "identical",
+			fields: []FieldDiff{
+				{Field: "street", Input: "1 Main St", Output: "1 MAIN ST"},
+				{Field: "city", Input: "Provo", Output: "Provo"},
+				{Field: "street2"},
+			},
+			expected: "",
+		},
+		{
+			name: "added, removed and changed nested fields",
+			fields: []FieldDiff{
+				{Field: "street", Input: "1 Main", Output: "1 Main St"},
+				{Field: "street2"},
+				{Field: "components.city_name", Input: "Provo", Output: "Provo"},
+				{Field: "components.plus4_code", Output: "1234"},
+				{Field: "secondary", Input: "Apt 5"},
+			},
+			expected: "" +
+				"~ street                 1 Main  ->  1 Main St  (corrected)\n" +
+				"  components.city_name   Provo   ->  Provo\n" +
+				"+ components.plus4_code          ->  1234  (added)\n" +
+				"- secondary              Apt 5   ->  (removed)\n",
+		},
+		{
+			name: "colored",
+			fields: []FieldDiff{
+				{Field: "zipcode", Input: "84601", Output: "84601"},
+				{Field: "plus4_code", Output: "1234"},
+			},
+			colored: true,
+			expected: "" +
+				"  zipcode     84601  ->  84601\n" +
+				ColorGreen + "+ plus4_code         ->  1234" + ColorReset + "\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := DumpDiff(test.fields, test.colored); actual != test.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", actual, test.expected)
+			}
+		})
+	}
+}
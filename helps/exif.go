@@ -0,0 +1,253 @@
+package helps
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// EXIF holds the few tags we care about from a photo.
+type EXIF struct {
+	Captured  string // RFC 3339, without an offset when the photo didn't record one (blank when unknown)
+	HasGPS    bool
+	Latitude  float64
+	Longitude float64
+}
+
+var ErrNoEXIF = errors.New("no EXIF data found")
+
+// ReadEXIF reads the EXIF data from a JPEG (or a TIFF-based file, like most camera raw formats).
+// For JPEGs, only the headers preceding the image data are read.
+func ReadEXIF(reader io.Reader) (*EXIF, error) {
+	var magic [2]byte
+	if _, err := io.ReadFull(reader, magic[:]); err != nil {
+		return nil, ErrNoEXIF
+	}
+	switch string(magic[:]) {
+	case "\xFF\xD8":
+		return readJPEGEXIF(reader)
+	case "II", "MM":
+		content, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		return parseTIFF(append(magic[:], content...))
+	default:
+		return nil, ErrNoEXIF
+	}
+}
+
+func readJPEGEXIF(reader io.Reader) (*EXIF, error) {
+	for {
+		var marker [4]byte
+		if _, err := io.ReadFull(reader, marker[:]); err != nil {
+			return nil, ErrNoEXIF
+		}
+		if marker[0] != 0xFF || marker[1] == 0xDA || marker[1] == 0xD9 {
+			return nil, ErrNoEXIF // the image data (or the end) has been reached
+		}
+		length := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		if length < 0 {
+			return nil, ErrNoEXIF
+		}
+		segment := make([]byte, length)
+		if _, err := io.ReadFull(reader, segment); err != nil {
+			return nil, ErrNoEXIF
+		}
+		if marker[1] == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return parseTIFF(segment[6:])
+		}
+	}
+}
+
+///////////////////
+
+const (
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+
+	tagGPSLatitudeRef  = 0x0001
+	tagGPSLatitude     = 0x0002
+	tagGPSLongitudeRef = 0x0003
+	tagGPSLongitude    = 0x0004
+	tagGPSTimeStamp    = 0x0007
+	tagGPSDateStamp    = 0x001D
+)
+
+// tiff reads the tags of the image file directories (IFDs) in a TIFF structure.
+type tiff struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+type ifdEntry struct {
+	kind   uint16
+	count  uint32
+	offset uint32 // or the value itself, when it fits in 4 bytes
+	raw    []byte // the 4 bytes holding the offset (or value)
+}
+
+func parseTIFF(data []byte) (*EXIF, error) {
+	if len(data) < 8 {
+		return nil, ErrNoEXIF
+	}
+	this := &tiff{data: data}
+	switch string(data[:2]) {
+	case "II":
+		this.order = binary.LittleEndian
+	case "MM":
+		this.order = binary.BigEndian
+	default:
+		return nil, ErrNoEXIF
+	}
+	if this.order.Uint16(data[2:]) != 42 {
+		return nil, ErrNoEXIF
+	}
+
+	root, err := this.directory(this.order.Uint32(data[4:]))
+	if err != nil {
+		return nil, err
+	}
+	exif := new(EXIF)
+	captured := this.ascii(root[tagDateTime])
+	offset := ""
+	if entry, found := root[tagExifIFD]; found {
+		if sub, err := this.directory(entry.offset); err == nil {
+			if original := this.ascii(sub[tagDateTimeOriginal]); original != "" {
+				captured = original
+				offset = this.ascii(sub[tagOffsetTimeOriginal])
+			}
+		}
+	}
+	exif.Captured = formatEXIFTime(captured, offset)
+
+	if entry, found := root[tagGPSIFD]; found {
+		if gps, err := this.directory(entry.offset); err == nil {
+			this.readGPS(gps, exif)
+		}
+	}
+	return exif, nil
+}
+
+func (this *tiff) directory(offset uint32) (map[uint16]ifdEntry, error) {
+	start := int(offset)
+	if start < 0 || start+2 > len(this.data) {
+		return nil, fmt.Errorf("invalid EXIF directory offset: %d", offset)
+	}
+	count := int(this.order.Uint16(this.data[start:]))
+	if available := (len(this.data) - start - 2) / 12; count > available {
+		count = available // the count is read from the file, so it can't be trusted to fit
+	}
+	entries := make(map[uint16]ifdEntry, count)
+	for i := 0; i < count; i++ {
+		at := start + 2 + i*12
+		entries[this.order.Uint16(this.data[at:])] = ifdEntry{
+			kind:   this.order.Uint16(this.data[at+2:]),
+			count:  this.order.Uint32(this.data[at+4:]),
+			offset: this.order.Uint32(this.data[at+8:]),
+			raw:    this.data[at+8 : at+12],
+		}
+	}
+	return entries, nil
+}
+
+// value is the entry's bytes, whether stored inline or at its offset.
+func (this *tiff) value(entry ifdEntry, size int) []byte {
+	if uint64(entry.count)*uint64(size) > uint64(len(this.data)) {
+		return nil
+	}
+	length := size * int(entry.count)
+	if length <= 4 {
+		return entry.raw[:length]
+	}
+	start := int(entry.offset)
+	if start < 0 || start+length > len(this.data) {
+		return nil
+	}
+	return this.data[start : start+length]
+}
+
+func (this *tiff) ascii(entry ifdEntry) string {
+	if entry.kind != 2 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(this.value(entry, 1)), "\x00"))
+}
+
+// rationals reads unsigned rationals (as used by all the GPS tags we read).
+func (this *tiff) rationals(entry ifdEntry) (values []float64, ok bool) {
+	if entry.kind != 5 {
+		return nil, false
+	}
+	raw := this.value(entry, 8)
+	if raw == nil {
+		return nil, false
+	}
+	for i := 0; i+8 <= len(raw); i += 8 {
+		numerator, denominator := this.order.Uint32(raw[i:]), this.order.Uint32(raw[i+4:])
+		if denominator == 0 {
+			return nil, false
+		}
+		values = append(values, float64(numerator)/float64(denominator))
+	}
+	return values, len(values) > 0
+}
+
+func (this *tiff) readGPS(gps map[uint16]ifdEntry, exif *EXIF) {
+	latitude, latitudeOK := this.degrees(gps[tagGPSLatitude], this.ascii(gps[tagGPSLatitudeRef]), "S")
+	longitude, longitudeOK := this.degrees(gps[tagGPSLongitude], this.ascii(gps[tagGPSLongitudeRef]), "W")
+	if latitudeOK && longitudeOK && latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180 {
+		exif.HasGPS = true
+		exif.Latitude, exif.Longitude = latitude, longitude
+	}
+
+	// The GPS clock is UTC, so it's the next best thing when the camera didn't record a capture time.
+	if exif.Captured == "" {
+		date := this.ascii(gps[tagGPSDateStamp])
+		clock, ok := this.rationals(gps[tagGPSTimeStamp])
+		if date != "" && ok && len(clock) == 3 {
+			stamp := fmt.Sprintf("%s %02d:%02d:%02d", date, int(clock[0]), int(clock[1]), int(clock[2]))
+			exif.Captured = formatEXIFTime(stamp, "Z")
+		}
+	}
+}
+
+// degrees converts the degrees, minutes and seconds of a GPS tag, negated in the given hemisphere.
+func (this *tiff) degrees(entry ifdEntry, reference, negative string) (float64, bool) {
+	parts, ok := this.rationals(entry)
+	if !ok {
+		return 0, false
+	}
+	degrees, scale := 0.0, 1.0
+	for i := 0; i < len(parts) && i < 3; i++ {
+		degrees += parts[i] / scale
+		scale *= 60
+	}
+	if strings.EqualFold(reference, negative) {
+		degrees = -degrees
+	}
+	return degrees, true
+}
+
+// formatEXIFTime converts "2006:01:02 15:04:05" (and an optional "-07:00" offset) to RFC 3339.
+func formatEXIFTime(value, offset string) string {
+	parsed, err := time.Parse("2006:01:02 15:04:05", value)
+	if err != nil {
+		return ""
+	}
+	if offset == "Z" {
+		return parsed.Format(time.RFC3339)
+	}
+	if zoned, err := time.Parse("2006:01:02 15:04:05-07:00", value+offset); err == nil {
+		return zoned.Format(time.RFC3339)
+	}
+	return parsed.Format("2006-01-02T15:04:05")
+}
@@ -0,0 +1,167 @@
+package helps
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+type testIFDEntry struct {
+	tag   uint16
+	kind  uint16
+	count uint32
+	value []byte
+}
+
+// buildTIFF lays out a TIFF header, a root directory pointing to a GPS directory,
+// and the GPS directory, with any values over 4 bytes following each directory.
+func buildTIFF(order binary.ByteOrder, gps []testIFDEntry) []byte {
+	data := []byte("II")
+	if order == binary.BigEndian {
+		data = []byte("MM")
+	}
+	data = appendUint16(order, data, 42)
+	data = appendUint32(order, data, 8)
+	gpsOffset := uint32(8 + 2 + 12 + 4)
+	data = appendIFD(order, data, []testIFDEntry{{tag: tagGPSIFD, kind: 4, count: 1, value: appendUint32(order, nil, gpsOffset)}})
+	return appendIFD(order, data, gps)
+}
+
+func appendIFD(order binary.ByteOrder, data []byte, entries []testIFDEntry) []byte {
+	values := uint32(len(data) + 2 + 12*len(entries) + 4)
+	var extra []byte
+	data = appendUint16(order, data, uint16(len(entries)))
+	for _, entry := range entries {
+		data = appendUint16(order, data, entry.tag)
+		data = appendUint16(order, data, entry.kind)
+		data = appendUint32(order, data, entry.count)
+		if len(entry.value) <= 4 {
+			data = append(data, append(entry.value, make([]byte, 4-len(entry.value))...)...)
+			continue
+		}
+		data = appendUint32(order, data, values+uint32(len(extra)))
+		extra = append(extra, entry.value...)
+	}
+	data = appendUint32(order, data, 0) // no next directory
+	return append(data, extra...)
+}
+
+func appendUint16(order binary.ByteOrder, data []byte, value uint16) []byte {
+	var raw [2]byte
+	order.PutUint16(raw[:], value)
+	return append(data, raw[:]...)
+}
+
+func appendUint32(order binary.ByteOrder, data []byte, value uint32) []byte {
+	var raw [4]byte
+	order.PutUint32(raw[:], value)
+	return append(data, raw[:]...)
+}
+
+func rationals(order binary.ByteOrder, pairs ...uint32) (raw []byte) {
+	for _, value := range pairs {
+		raw = appendUint32(order, raw, value)
+	}
+	return raw
+}
+
+func gpsEntries(order binary.ByteOrder) []testIFDEntry {
+	return []testIFDEntry{
+		{tag: tagGPSLatitudeRef, kind: 2, count: 2, value: []byte("N\x00")},
+		{tag: tagGPSLatitude, kind: 5, count: 3, value: rationals(order, 40, 1, 15, 1, 0, 1)},
+		{tag: tagGPSLongitudeRef, kind: 2, count: 2, value: []byte("W\x00")},
+		{tag: tagGPSLongitude, kind: 5, count: 3, value: rationals(order, 111, 1, 4020, 100, 0, 1)},
+		{tag: tagGPSTimeStamp, kind: 5, count: 3, value: rationals(order, 12, 1, 34, 1, 56, 1)},
+		{tag: tagGPSDateStamp, kind: 2, count: 11, value: []byte("2024:05:06\x00")},
+	}
+}
+
+func buildJPEG(tiff []byte) []byte {
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x04, 0x00, 0x00} // an empty APP0 segment to skip
+	jpeg = append(jpeg, 0xFF, 0xE1)
+	jpeg = appendUint16(binary.BigEndian, jpeg, uint16(len(segment)+2))
+	jpeg = append(jpeg, segment...)
+	return append(jpeg, 0xFF, 0xDA, 0x00, 0x02)
+}
+
+func TestReadEXIF(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		tiff := buildTIFF(order, gpsEntries(order))
+		for name, content := range map[string][]byte{"jpeg": buildJPEG(tiff), "tiff": tiff} {
+			exif, err := ReadEXIF(bytes.NewReader(content))
+			if err != nil {
+				t.Errorf("%s %s: unexpected error: %v", order, name, err)
+				continue
+			}
+			if !exif.HasGPS || math.Abs(exif.Latitude-40.25) > 1e-9 || math.Abs(exif.Longitude+111.67) > 1e-9 {
+				t.Errorf("%s %s: got GPS %v (%v, %v), want (40.25, -111.67)", order, name, exif.HasGPS, exif.Latitude, exif.Longitude)
+			}
+			if exif.Captured != "2024-05-06T12:34:56Z" {
+				t.Errorf("%s %s: got capture time %q", order, name, exif.Captured)
+			}
+		}
+	}
+}
+
+func TestReadEXIFTruncated(t *testing.T) {
+	content := buildJPEG(buildTIFF(binary.LittleEndian, gpsEntries(binary.LittleEndian)))
+	for length := 0; length < len(content); length++ {
+		exif, err := ReadEXIF(bytes.NewReader(content[:length]))
+		if err == nil && exif.HasGPS && (exif.Latitude != 40.25 || math.Abs(exif.Longitude+111.67) > 1e-9) {
+			t.Errorf("length %d: got unexpected coordinates (%v, %v)", length, exif.Latitude, exif.Longitude)
+		}
+	}
+}
+
+func TestReadEXIFCorrupt(t *testing.T) {
+	order := binary.LittleEndian
+	corrupt := func(change func(entries []testIFDEntry)) []byte {
+		entries := gpsEntries(order)
+		change(entries)
+		return buildJPEG(buildTIFF(order, entries))
+	}
+	hugeDirectory := buildTIFF(order, gpsEntries(order))
+	order.PutUint16(hugeDirectory[26:], 0xFFFF)
+	missingDirectory := buildTIFF(order, nil)
+	order.PutUint32(missingDirectory[8+2+8:], 0xFFFFFFF0)
+
+	for name, test := range map[string]struct {
+		content  []byte
+		gps      bool
+		captured string
+	}{
+		"zero denominator":      {content: corrupt(func(entries []testIFDEntry) { entries[1].value = rationals(order, 40, 0, 15, 1, 0, 1) }), captured: "2024-05-06T12:34:56Z"},
+		"huge rational count":   {content: corrupt(func(entries []testIFDEntry) { entries[3].count = 0xFFFFFFFF }), captured: "2024-05-06T12:34:56Z"},
+		"wrong latitude type":   {content: corrupt(func(entries []testIFDEntry) { entries[1].kind = 3 }), captured: "2024-05-06T12:34:56Z"},
+		"latitude out of range": {content: corrupt(func(entries []testIFDEntry) { entries[1].value = rationals(order, 95, 1, 0, 1, 0, 1) }), captured: "2024-05-06T12:34:56Z"},
+		"huge ascii count":      {content: corrupt(func(entries []testIFDEntry) { entries[5].count = 0x7FFFFFFF }), gps: true},
+		"huge directory count":  {content: buildJPEG(hugeDirectory), gps: true, captured: "2024-05-06T12:34:56Z"},
+		"missing GPS directory": {content: buildJPEG(missingDirectory)},
+	} {
+		exif, err := ReadEXIF(bytes.NewReader(test.content))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if exif.HasGPS != test.gps || exif.Captured != test.captured {
+			t.Errorf("%s: got GPS %v and capture time %q, want %v and %q", name, exif.HasGPS, exif.Captured, test.gps, test.captured)
+		}
+	}
+}
+
+func TestReadEXIFNotFound(t *testing.T) {
+	for name, content := range map[string][]byte{
+		"empty":          nil,
+		"not an image":   []byte("hello, world"),
+		"no APP1":        {0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x04, 0x00, 0x00, 0xFF, 0xDA, 0x00, 0x02},
+		"bad length":     {0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x01},
+		"not TIFF":       buildJPEG([]byte("XX\x2A\x00\x08\x00\x00\x00")),
+		"wrong TIFF tag": buildJPEG([]byte("II\x2B\x00\x08\x00\x00\x00")),
+	} {
+		if _, err := ReadEXIF(bytes.NewReader(content)); err != ErrNoEXIF {
+			t.Errorf("%s: got error %v, want ErrNoEXIF", name, err)
+		}
+	}
+}
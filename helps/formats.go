@@ -0,0 +1,34 @@
+package helps
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatGeoJSON = "geojson"
+	FormatKML     = "kml"
+	FormatGPX     = "gpx"
+)
+
+// PointFormats are the output formats (besides plain JSON) that render geocoded points.
+var PointFormats = map[string]func([]Point) string{
+	FormatGeoJSON: DumpGeoJSON,
+	FormatKML:     DumpKML,
+	FormatGPX:     DumpGPX,
+}
+
+func PointFormatNames() string {
+	names := []string{FormatJSON}
+	for name := range PointFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return strings.Join(names, ", ")
+}
+
+func IsPointFormat(format string) bool {
+	_, found := PointFormats[format]
+	return found || format == FormatJSON
+}
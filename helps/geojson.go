@@ -0,0 +1,58 @@
+package helps
+
+import "strings"
+
+// Point is a geocoded result reduced to what map formats need.
+type Point struct {
+	Latitude    float64
+	Longitude   float64
+	Name        string
+	Description string
+	Properties  map[string]interface{}
+}
+
+// Describe renders alternating name/value pairs one per line, skipping blank values.
+func Describe(pairs ...string) string {
+	var lines []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			lines = append(lines, pairs[i]+": "+pairs[i+1])
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+func NewFeatureCollection(points []Point) *FeatureCollection {
+	collection := &FeatureCollection{Type: "FeatureCollection", Features: []*Feature{}}
+	for _, point := range points {
+		collection.Features = append(collection.Features, &Feature{
+			Type: "Feature",
+			Geometry: Geometry{
+				Type:        "Point",
+				Coordinates: []float64{point.Longitude, point.Latitude}, // GeoJSON positions are longitude first.
+			},
+			Properties: point.Properties,
+		})
+	}
+	return collection
+}
+
+func DumpGeoJSON(points []Point) string {
+	return DumpJSON(NewFeatureCollection(points))
+}
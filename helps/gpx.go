@@ -0,0 +1,42 @@
+package helps
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+type gpxDocument struct {
+	XMLName   xml.Name      `xml:"gpx"`
+	Namespace string        `xml:"xmlns,attr"`
+	Version   string        `xml:"version,attr"`
+	Creator   string        `xml:"creator,attr"`
+	Waypoints []gpxWaypoint `xml:"wpt"`
+}
+
+type gpxWaypoint struct {
+	Latitude    string `xml:"lat,attr"`
+	Longitude   string `xml:"lon,attr"`
+	Name        string `xml:"name,omitempty"`
+	Description string `xml:"desc,omitempty"`
+}
+
+func DumpGPX(points []Point) string {
+	document := gpxDocument{
+		Namespace: "http://www.topografix.com/GPX/1/1",
+		Version:   "1.1",
+		Creator:   "smarty-cli",
+	}
+	for _, point := range points {
+		document.Waypoints = append(document.Waypoints, gpxWaypoint{
+			Latitude:    formatCoordinate(point.Latitude),
+			Longitude:   formatCoordinate(point.Longitude),
+			Name:        point.Name,
+			Description: point.Description,
+		})
+	}
+	return dumpXML(document)
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
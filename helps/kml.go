@@ -0,0 +1,38 @@
+package helps
+
+import (
+	"encoding/xml"
+	"log"
+)
+
+type kmlDocument struct {
+	XMLName    xml.Name       `xml:"kml"`
+	Namespace  string         `xml:"xmlns,attr"`
+	Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name        string `xml:"name"`
+	Description string `xml:"description,omitempty"`
+	Coordinates string `xml:"Point>coordinates"`
+}
+
+func DumpKML(points []Point) string {
+	document := kmlDocument{Namespace: "http://www.opengis.net/kml/2.2"}
+	for _, point := range points {
+		document.Placemarks = append(document.Placemarks, kmlPlacemark{
+			Name:        point.Name,
+			Description: point.Description,
+			Coordinates: formatCoordinate(point.Longitude) + "," + formatCoordinate(point.Latitude),
+		})
+	}
+	return dumpXML(document)
+}
+
+func dumpXML(v interface{}) string {
+	dump, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Panic(err)
+	}
+	return xml.Header + string(dump)
+}
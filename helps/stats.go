@@ -0,0 +1,36 @@
+package helps
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Percentile uses the nearest-rank method; p is in the range (0, 100].
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// SummarizeCounts lists each count by name, alphabetically (ie. "matched: 3, unmatched: 1").
+func SummarizeCounts(counts map[string]int) string {
+	var names []string
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var summary []string
+	for _, name := range names {
+		summary = append(summary, fmt.Sprintf("%s: %d", name, counts[name]))
+	}
+	return strings.Join(summary, ", ")
+}
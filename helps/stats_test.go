@@ -0,0 +1,18 @@
+package helps
+
+import "testing"
+
+func TestSummarizeCounts(t *testing.T) {
+	for _, test := range []struct {
+		counts   map[string]int
+		expected string
+	}{
+		{counts: nil, expected: ""},
+		{counts: map[string]int{"ok": 2}, expected: "ok: 2"},
+		{counts: map[string]int{"unmatched": 1, "matched": 3, "far": 0}, expected: "far: 0, matched: 3, unmatched: 1"},
+	} {
+		if actual := SummarizeCounts(test.counts); actual != test.expected {
+			t.Errorf("got %q, want %q", actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package helps
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Table is a CSV document whose first row is a header of column names.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+func ReadTable(reader io.Reader) (*Table, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	table := new(Table)
+	if len(records) > 0 {
+		table.Header, table.Rows = records[0], records[1:]
+	}
+	return table, nil
+}
+
+func (this *Table) Write(writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(this.Header); err != nil {
+		return err
+	}
+	if err := csvWriter.WriteAll(this.Rows); err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
+
+// Column returns the index of the named column (case-insensitive) or -1.
+func (this *Table) Column(name string) int {
+	for index, column := range this.Header {
+		if strings.EqualFold(strings.TrimSpace(column), name) {
+			return index
+		}
+	}
+	return -1
+}
+
+func (this *Table) Get(row []string, name string) string {
+	index := this.Column(name)
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return row[index]
+}
+
+// Values presents a row keyed by lower-cased column name,
+// the same shape as the -query and -url inputs.
+func (this *Table) Values(row []string) url.Values {
+	values := make(url.Values)
+	for index, column := range this.Header {
+		if index < len(row) {
+			values.Set(strings.ToLower(strings.TrimSpace(column)), row[index])
+		}
+	}
+	return values
+}
+
+// ReadTableFile reads the CSV file at the path (stdin when the path is blank or "-"),
+// which must have a column for each of the required names.
+func ReadTableFile(path string, required ...string) (*Table, error) {
+	reader, err := OpenInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	table, err := ReadTable(reader)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range required {
+		if table.Column(column) < 0 {
+			return nil, fmt.Errorf("the input has no column named: %s", column)
+		}
+	}
+	return table, nil
+}
+
+// WriteTableFile writes the table as CSV to the path (stdout when the path is "-").
+func WriteTableFile(path string, table *Table) error {
+	writer, err := CreateOutput(path)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	return table.Write(writer)
+}
+
+// OpenInput opens the named file, or stdin when the path is blank or "-".
+func OpenInput(path string) (io.ReadCloser, error) {
+	if path == "" || path == "-" {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// CreateOutput creates the named file, or returns stdout when the path is "-".
+func CreateOutput(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopWriteCloser{Writer: os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,71 @@
+package helps
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Keystrokes, as read by ReadKey from a terminal in raw mode.
+const (
+	KeyEnter     = "\r"
+	KeyInterrupt = "\x03" // ctrl-c
+	KeyEOF       = "\x04" // ctrl-d
+	KeyHome      = "\x01" // ctrl-a
+	KeyEnd       = "\x05" // ctrl-e
+	KeyBackspace = "\x7f"
+	KeyUp        = "\x1b[A"
+	KeyDown      = "\x1b[B"
+	KeyRight     = "\x1b[C"
+	KeyLeft      = "\x1b[D"
+)
+
+// ReadKey reads one keystroke: a rune or an escape sequence (ie. an arrow key).
+func ReadKey(reader *bufio.Reader) (string, error) {
+	r, _, err := reader.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	key := string(r)
+	switch key {
+	case "\x1b":
+		if next, _ := reader.Peek(2); len(next) == 2 && next[0] == '[' {
+			_, _ = reader.Discard(2)
+			key += string(next)
+		}
+	case "\n":
+		key = KeyEnter
+	case "\b":
+		key = KeyBackspace
+	}
+	return key, nil
+}
+
+// RawTerminal switches the controlling terminal to raw mode (via stty, to avoid a platform
+// specific dependency) and returns a function that restores the previous settings.
+// Raw mode doesn't echo the final newline, so callers should write one once restored.
+func RawTerminal() (restore func(), err error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("interactive mode requires a terminal: %w", err)
+	}
+	if _, err = stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	restored := false
+	return func() {
+		if !restored {
+			restored = true
+			_, _ = stty(strings.TrimSpace(saved))
+		}
+	}, nil
+}
+
+func stty(args ...string) (string, error) {
+	command := exec.Command("stty", args...)
+	command.Stdin = os.Stdin
+	output, err := command.Output()
+	return string(output), err
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ListFlag is a flag.Value for list-valued fields. Values accumulate across repeated
+// flags (-city_filter Provo -city_filter Orem) and each occurrence may also hold several
+// values split on the separator (-city_filter Provo,Orem). A backslash escapes a literal
+// separator or backslash (-input 'notes\, 2024.txt'). Blank values are dropped.
+type ListFlag struct {
+	Values []string
+
+	separator rune
+	explicit  bool
+	validate  func(string) (string, error)
+}
+
+// NewListFlag returns a list holding the defaults until the flag is first set.
+func NewListFlag(separator rune, defaults ...string) *ListFlag {
+	return &ListFlag{Values: defaults, separator: separator}
+}
+
+// NewFilterListFlag returns a list of comma separated values (ie. cities or states). The SDK
+// sends these joined by commas, so a value with a comma of its own (even an escaped one)
+// would be split again by the API and is rejected instead.
+func NewFilterListFlag() *ListFlag {
+	list := NewListFlag(',')
+	list.validate = rejectCommas
+	return list
+}
+
+// NewPreferenceListFlag returns a list of 'CITY,ST' pairs (or lone 'ST' values) separated
+// by semicolons, as expected by the US Autocomplete API prefer field.
+func NewPreferenceListFlag() *ListFlag {
+	list := NewListFlag(';')
+	list.validate = parsePreference
+	return list
+}
+
+func (this *ListFlag) String() string {
+	if this == nil {
+		return ""
+	}
+	var escaped []string
+	for _, value := range this.Values {
+		value = strings.Replace(value, `\`, `\\`, -1)
+		value = strings.Replace(value, string(this.separator), `\`+string(this.separator), -1)
+		escaped = append(escaped, value)
+	}
+	return strings.Join(escaped, string(this.separator))
+}
+
+func (this *ListFlag) Set(raw string) error {
+	if !this.explicit {
+		this.Values, this.explicit = nil, true
+	}
+	for _, value := range splitEscaped(raw, this.separator) {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if this.validate != nil {
+			var err error
+			if value, err = this.validate(value); err != nil {
+				return err
+			}
+		}
+		this.Values = append(this.Values, value)
+	}
+	return nil
+}
+
+// SetAll sets each of the raw values in turn, as when a query string key is repeated.
+func (this *ListFlag) SetAll(raw []string) error {
+	for _, value := range raw {
+		if err := this.Set(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitEscaped(raw string, separator rune) (values []string) {
+	current := new(strings.Builder)
+	escaped := false
+	for _, r := range raw {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == separator:
+			values = append(values, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if escaped {
+		current.WriteRune('\\')
+	}
+	return append(values, current.String())
+}
+
+func rejectCommas(value string) (string, error) {
+	if strings.Contains(value, ",") {
+		return "", fmt.Errorf("%q can't contain a comma (the API would split it into separate values)", value)
+	}
+	return value, nil
+}
+
+// parsePreference accepts 'CITY,ST' or 'ST'. The API splits each preference at its comma,
+// so the city can't contain one of its own.
+func parsePreference(value string) (string, error) {
+	split := strings.LastIndex(value, ",")
+	city, state := "", value
+	if split >= 0 {
+		city, state = strings.TrimSpace(value[:split]), strings.TrimSpace(value[split+1:])
+	}
+	if len(state) != 2 {
+		return "", fmt.Errorf("preference %q should be 'CITY,ST' or 'ST' (with a two-letter state)", value)
+	}
+	if strings.Contains(city, ",") {
+		return "", fmt.Errorf("preference %q can't have a comma in the city (the API would split it)", value)
+	}
+	if split >= 0 && city == "" {
+		return "", fmt.Errorf("preference %q has a blank city", value)
+	}
+	if city == "" {
+		return strings.ToUpper(state), nil
+	}
+	return city + "," + strings.ToUpper(state), nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestListFlag(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		list     func() *ListFlag
+		args     []string
+		expected []string
+	}{
+		{name: "defaults when unset", list: func() *ListFlag { return NewListFlag(',', "a", "b") }, expected: []string{"a", "b"}},
+		{name: "set replaces defaults", list: func() *ListFlag { return NewListFlag(',', "a") }, args: []string{"b"}, expected: []string{"b"}},
+		{name: "separated", list: func() *ListFlag { return NewListFlag(',') }, args: []string{"Provo,Orem"}, expected: []string{"Provo", "Orem"}},
+		{name: "repeated", list: func() *ListFlag { return NewListFlag(',') }, args: []string{"Provo", "Orem,Lehi"}, expected: []string{"Provo", "Orem", "Lehi"}},
+		{name: "blanks and spaces", list: func() *ListFlag { return NewListFlag(',') }, args: []string{" Provo , ,", ""}, expected: []string{"Provo"}},
+		{name: "escaped separator", list: func() *ListFlag { return NewListFlag(',') }, args: []string{`notes\, 2024.txt,other.txt`}, expected: []string{"notes, 2024.txt", "other.txt"}},
+		{name: "escaped backslash", list: func() *ListFlag { return NewListFlag(',') }, args: []string{`a\\,b`}, expected: []string{`a\`, "b"}},
+		{name: "trailing backslash", list: func() *ListFlag { return NewListFlag(',') }, args: []string{`a\`}, expected: []string{`a\`}},
+		{name: "other separator", list: func() *ListFlag { return NewListFlag(';') }, args: []string{"a=1&b=2;c=3"}, expected: []string{"a=1&b=2", "c=3"}},
+		{name: "filters", list: NewFilterListFlag, args: []string{"Provo,Orem", "Lehi"}, expected: []string{"Provo", "Orem", "Lehi"}},
+		{name: "preferences", list: NewPreferenceListFlag, args: []string{"Provo,ut;ca", " Orem , UT "}, expected: []string{"Provo,UT", "CA", "Orem,UT"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			list := test.list()
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.Var(list, "list", "")
+			var args []string
+			for _, arg := range test.args {
+				args = append(args, "-list", arg)
+			}
+			if err := set.Parse(args); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(list.Values, test.expected) {
+				t.Errorf("got %q, want %q", list.Values, test.expected)
+			}
+		})
+	}
+}
+
+func TestListFlagRejects(t *testing.T) {
+	for _, test := range []struct {
+		list func() *ListFlag
+		raw  string
+	}{
+		{list: NewFilterListFlag, raw: `Washington\, D.C.`},
+		{list: NewPreferenceListFlag, raw: "Provo,Utah"},
+		{list: NewPreferenceListFlag, raw: "Utah"},
+		{list: NewPreferenceListFlag, raw: ",UT"},
+		{list: NewPreferenceListFlag, raw: "Provo,"},
+		{list: NewPreferenceListFlag, raw: "Washington, D.C.,DC"},
+		{list: NewPreferenceListFlag, raw: "Provo,UT;Orem"},
+	} {
+		if err := test.list().Set(test.raw); err == nil {
+			t.Errorf("%q: expected an error", test.raw)
+		}
+	}
+}
+
+func TestListFlagString(t *testing.T) {
+	list := NewListFlag(',')
+	if err := list.SetAll([]string{`notes\, 2024.txt`, `a\\b`}); err != nil {
+		t.Fatal(err)
+	}
+	if actual := list.String(); actual != `notes\, 2024.txt,a\\b` {
+		t.Errorf("got %q", actual)
+	}
+	var unset *ListFlag
+	if unset.String() != "" {
+		t.Error("a nil list should be blank")
+	}
+}
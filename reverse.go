@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"sync"
+	"time"
+
+	reverse "github.com/smartystreets/smartystreets-go-sdk/us-reverse-geo-api"
+)
+
+// SendReverseLookups sends each lookup, several at a time (and no more than rate per second, when positive).
+// A failed lookup doesn't stop the others; its error is returned at the same index as the lookup (nil otherwise).
+func SendReverseLookups(client *reverse.Client, lookups []*reverse.Lookup, concurrency int, rate float64) []error {
+	errs := make([]error, len(lookups))
+	work := make(chan int)
+	var waiter sync.WaitGroup
+	for i := 0; i < concurrency || i == 0; i++ {
+		waiter.Add(1)
+		go func() {
+			defer waiter.Done()
+			for index := range work {
+				errs[index] = client.SendLookup(lookups[index])
+			}
+		}()
+	}
+	var throttle <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+	for index := range lookups {
+		if throttle != nil {
+			<-throttle
+		}
+		work <- index
+	}
+	close(work)
+	waiter.Wait()
+	return errs
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-street-api"
+)
+
+// NewStreetLookup maps query string style values (street, city, state, zipcode, etc...) onto a US Street lookup.
+func NewStreetLookup(values url.Values) *street.Lookup {
+	lookup := new(street.Lookup)
+	lookup.Street = values.Get("street")
+	if lookup.Street == "" {
+		lookup.Street = values.Get("address") // a single-line freeform address
+	}
+	lookup.Street2 = values.Get("street2")
+	lookup.City = values.Get("city")
+	lookup.State = values.Get("state")
+	lookup.ZIPCode = values.Get("zipcode")
+	lookup.LastLine = values.Get("lastline")
+	lookup.Addressee = values.Get("addressee")
+	lookup.Urbanization = values.Get("urbanization")
+	lookup.Secondary = values.Get("secondary")
+	lookup.InputID = values.Get("input_id")
+	lookup.MatchStrategy = street.MatchStrategy(values.Get("match"))
+	lookup.MaxCandidates, _ = strconv.Atoi(values.Get("candidates"))
+	return lookup
+}
+
+// SendStreetLookups sends any number of lookups, split into batches no larger than the API allows.
+func SendStreetLookups(client *street.Client, lookups []*street.Lookup) error {
+	batch := street.NewBatch()
+	for _, lookup := range lookups {
+		batch.Append(lookup)
+		if batch.Length() < street.MaxBatchSize {
+			continue
+		}
+		if err := client.SendBatch(batch); err != nil {
+			return err
+		}
+		batch = street.NewBatch()
+	}
+	if batch.Length() == 0 {
+		return nil
+	}
+	return client.SendBatch(batch)
+}
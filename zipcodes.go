@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"net/url"
+
+	"github.com/smartystreets/smartystreets-go-sdk/us-zipcode-api"
+)
+
+// NewZIPCodeLookup maps query string style values (city, state, zipcode, input_id) onto a US ZIP Code lookup.
+// The zipCode spelling accepted by earlier versions still works in place of zipcode.
+func NewZIPCodeLookup(values url.Values) *zipcode.Lookup {
+	zipCode := values.Get("zipcode")
+	if zipCode == "" {
+		zipCode = values.Get("zipCode")
+	}
+	return &zipcode.Lookup{
+		City:    values.Get("city"),
+		State:   values.Get("state"),
+		ZIPCode: zipCode,
+		InputID: values.Get("input_id"),
+	}
+}
+
+// SendZIPCodeLookups sends any number of lookups, split into batches no larger than the API allows.
+func SendZIPCodeLookups(client *zipcode.Client, lookups []*zipcode.Lookup) error {
+	batch := zipcode.NewBatch()
+	for _, lookup := range lookups {
+		batch.Append(lookup)
+		if batch.Length() < zipcode.MaxBatchSize {
+			continue
+		}
+		if err := client.SendBatch(batch); err != nil {
+			return err
+		}
+		batch = zipcode.NewBatch()
+	}
+	if batch.Length() == 0 {
+		return nil
+	}
+	return client.SendBatch(batch)
+}
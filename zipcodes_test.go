@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestNewZIPCodeLookup(t *testing.T) {
+	for _, test := range []struct {
+		query    string
+		expected string
+	}{
+		{query: "zipcode=84601", expected: "84601"},
+		{query: "zipCode=84601", expected: "84601"},
+		{query: "zipcode=84601&zipCode=84604", expected: "84601"},
+		{query: "city=Provo", expected: ""},
+	} {
+		values, _ := url.ParseQuery(test.query)
+		if actual := NewZIPCodeLookup(values).ZIPCode; actual != test.expected {
+			t.Errorf("%s: got %q, want %q", test.query, actual, test.expected)
+		}
+	}
+}